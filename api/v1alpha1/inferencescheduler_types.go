@@ -17,8 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // InferenceSchedulerSpec defines the desired state of InferenceScheduler
@@ -27,6 +30,16 @@ type InferenceSchedulerSpec struct {
 	// +kubebuilder:validation:Required
 	ModelServer ModelServerSpec `json:"modelServer"`
 
+	// ExtraModelServers are additional model servers behind the same EPP and InferencePool, e.g. a
+	// second model variant for an A/B test. Each gets its own Deployment and Service and takes the
+	// settings it does not override from ModelServer. Set ModelServer.ServedModelNames to serve
+	// every variant under the same model name, since the EPP may route a request to any of them
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	ExtraModelServers []ExtraModelServerSpec `json:"extraModelServers,omitempty"`
+
 	// EndpointPicker configuration for intelligent routing
 	// +optional
 	EndpointPicker EndpointPickerSpec `json:"endpointPicker,omitempty"`
@@ -34,6 +47,134 @@ type InferenceSchedulerSpec struct {
 	// Gateway configuration
 	// +optional
 	Gateway GatewaySpec `json:"gateway,omitempty"`
+
+	// RolloutOrder controls how an update of both the model server and the EPP is rolled out.
+	// Parallel updates both at once. ModelServerFirst and EndpointPickerFirst hold the update of
+	// one component until the other has finished rolling out its new images, avoiding version skew.
+	// A component whose rollout is paused, see PauseRollout, does not hold the other
+	// +kubebuilder:validation:Enum=Parallel;ModelServerFirst;EndpointPickerFirst
+	// +kubebuilder:default=Parallel
+	// +optional
+	RolloutOrder string `json:"rolloutOrder,omitempty"`
+
+	// RoutingOrder controls when the InferencePool, Gateway and HTTPRoutes are created.
+	// AfterModelServerReady waits until every model server is ready. Immediate creates them while
+	// the model server is still starting, e.g. so the Gateway address is known early. Either way
+	// GatewayReady is False while no model server is ready to serve the routes
+	// +kubebuilder:validation:Enum=AfterModelServerReady;Immediate
+	// +kubebuilder:default=AfterModelServerReady
+	// +optional
+	RoutingOrder string `json:"routingOrder,omitempty"`
+
+	// OwnerReferences overrides how the owner reference to the InferenceScheduler is set on
+	// child resources of a given kind. Kinds without an entry get a controller reference that
+	// blocks owner deletion
+	// +listType=map
+	// +listMapKey=kind
+	// +optional
+	OwnerReferences []OwnerReferencePolicy `json:"ownerReferences,omitempty"`
+
+	// MaintenanceMode removes the InferencePool backend from the HTTPRoute, so the Gateway answers
+	// matching requests with 503 while the model server is scaled down or updated. All other
+	// resources are kept and reconciled as usual. Disabling it restores routing to the pool
+	// +optional
+	MaintenanceMode bool `json:"maintenanceMode,omitempty"`
+
+	// ImagePullSecrets are used to pull the model server and EPP images from private registries.
+	// A component that sets its own imagePullSecrets uses those instead
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Monitoring configures monitoring resources generated for this InferenceScheduler
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+}
+
+// AutoscalingSpec defines the HorizontalPodAutoscaler of the model server
+type AutoscalingSpec struct {
+	// MinReplicas is the lower limit of the model server replicas
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the model server replicas
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metrics the autoscaler scales on, e.g. GPU utilization or queue depth served by a custom
+	// metrics adapter. Defaults to 80% average CPU utilization
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+}
+
+// PodDisruptionBudgetSpec defines the policy/v1 PodDisruptionBudget of a component. At most one of
+// MinAvailable and MaxUnavailable may be set. If neither is, MaxUnavailable is 1 and no budget is
+// created for a single replica, where it could not allow any disruption anyway
+type PodDisruptionBudgetSpec struct {
+	// MinAvailable is the number or percentage of pods that must stay available
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that may be unavailable
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// MonitoringSpec defines the monitoring resources generated for an InferenceScheduler
+type MonitoringSpec struct {
+	// PrometheusRule creates a monitoring.coreos.com/v1 PrometheusRule with default alerts: the
+	// InferenceScheduler not Ready, model server replicas below the desired count, and the EPP
+	// down. The replica alerts use kube-state-metrics. Nothing is created if the Prometheus
+	// Operator CRDs are not installed
+	// +optional
+	PrometheusRule bool `json:"prometheusRule,omitempty"`
+
+	// ServiceMonitor creates a monitoring.coreos.com/v1 ServiceMonitor named <name>-metrics that
+	// scrapes the EPP metrics port. Nothing is created if the Prometheus Operator CRDs are not installed
+	// +optional
+	ServiceMonitor bool `json:"serviceMonitor,omitempty"`
+
+	// ScrapeModelServer adds the vLLM /metrics endpoint of the model server Service to the ServiceMonitor
+	// +optional
+	ScrapeModelServer bool `json:"scrapeModelServer,omitempty"`
+
+	// ScrapeAnnotations adds the prometheus.io/scrape, prometheus.io/port and prometheus.io/path
+	// annotations for the vLLM /metrics endpoint to the model server pods, for Prometheus
+	// configurations that discover pods by annotation. It can be combined with ServiceMonitor
+	// +optional
+	ScrapeAnnotations bool `json:"scrapeAnnotations,omitempty"`
+
+	// Dashboard creates a ConfigMap named <name>-dashboard with a Grafana dashboard of the request
+	// latency, queue depth, KV cache utilization and prefix cache hit rate of this InferenceScheduler.
+	// It is labeled grafana_dashboard: "1" for the Grafana dashboard sidecar. The model server
+	// panels need ScrapeModelServer
+	// +optional
+	Dashboard bool `json:"dashboard,omitempty"`
+
+	// Labels are added to the generated monitoring resources, e.g. to match the ruleSelector of
+	// a Prometheus instance
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// OwnerReferencePolicy defines the owner reference set on child resources of one kind
+type OwnerReferencePolicy struct {
+	// Kind of the child resource, e.g. Deployment or HTTPRoute
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;HorizontalPodAutoscaler;PersistentVolumeClaim;Service;ServiceAccount;Role;RoleBinding;ConfigMap;InferencePool;Gateway;HTTPRoute;PrometheusRule;ServiceMonitor;PodDisruptionBudget
+	Kind string `json:"kind"`
+
+	// Controller sets the reference as the controller reference. A non-controller reference
+	// still garbage collects the child, but changes to it no longer trigger a reconcile
+	// +kubebuilder:default=true
+	// +optional
+	Controller *bool `json:"controller,omitempty"`
+
+	// BlockOwnerDeletion makes foreground deletion of the InferenceScheduler wait for the child
+	// to be deleted first
+	// +kubebuilder:default=true
+	// +optional
+	BlockOwnerDeletion *bool `json:"blockOwnerDeletion,omitempty"`
 }
 
 // ModelServerSpec defines the model server configuration
@@ -43,20 +184,45 @@ type ModelServerSpec struct {
 	// +kubebuilder:default=vllm
 	Type string `json:"type,omitempty"`
 
-	// ModelName is the HuggingFace model name to deploy
+	// ModelName is the HuggingFace model to deploy, as a repo id like org/model or an absolute path
+	// to a model in the container. Other names are reported by the ModelNameValid condition
 	// +kubebuilder:validation:Required
 	ModelName string `json:"modelName"`
 
+	// ServedModelNames are the model ids the server answers to in the OpenAI API
+	// If not specified, the server answers to ModelName only
+	// +optional
+	// +kubebuilder:validation:items:MinLength=1
+	ServedModelNames []string `json:"servedModelNames,omitempty"`
+
 	// Replicas is the number of model server instances
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=2
 	Replicas int32 `json:"replicas,omitempty"`
 
+	// ReplicasFrom reads the replica count from a ConfigMap key, for capacity managed outside the
+	// InferenceScheduler. It overrides Replicas, and changes to the ConfigMap are applied right away.
+	// If the reference is optional and the ConfigMap or key is missing, Replicas is used
+	// +optional
+	ReplicasFrom *corev1.ConfigMapKeySelector `json:"replicasFrom,omitempty"`
+
+	// Autoscaling scales the model server with a HorizontalPodAutoscaler. While it is set the
+	// replica count is left to the autoscaler, and Replicas and ReplicasFrom are ignored
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
 	// Image is the container image for the model server
 	// +kubebuilder:default="vllm/vllm-openai:latest"
 	Image string `json:"image,omitempty"`
 
+	// ImagePullSecrets for the model server image, replacing spec.imagePullSecrets
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
 	// Resources defines resource requirements for model server pods
+	// CPU and memory that are not set take the operator's defaults, so the pods are admitted in
+	// namespaces whose ResourceQuota requires them. Requests default to 500m/2Gi; limits are only
+	// defaulted if the operator is started with --model-server-default-limits
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
@@ -71,25 +237,388 @@ type ModelServerSpec struct {
 	// +kubebuilder:validation:Type=number
 	GPUMemoryUtilization *float64 `json:"gpuMemoryUtilization,omitempty"`
 
+	// TensorParallelSize shards the model across this many GPUs (--tensor-parallel-size). The
+	// nvidia.com/gpu limit of the vLLM container defaults to it unless Resources set a GPU request or limit
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TensorParallelSize *int32 `json:"tensorParallelSize,omitempty"`
+
+	// PodDisruptionBudget limits voluntary evictions of the model server pods, e.g. during node
+	// drains, so the model does not have to be reloaded on all replicas at once
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// ExtraArgs are appended to the generated vLLM arguments, e.g. "--max-model-len=8192" or
+	// "--dtype", "bfloat16". A generated flag that ExtraArgs also set is dropped, so ExtraArgs win.
+	// Set the model and port through ModelName and Port, which the probes and Services rely on
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// ExtraEnv is merged into the vLLM container environment. A variable that the operator also
+	// sets is replaced, so ExtraEnv wins
+	// +optional
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+
+	// DisableLogRequests stops vLLM from logging request prompts and parameters (--disable-log-requests)
+	// +optional
+	DisableLogRequests bool `json:"disableLogRequests,omitempty"`
+
+	// LogFormat of the model server logs. json configures vLLM's Python logging with a JSON
+	// formatter through VLLM_LOGGING_CONFIG_PATH and a generated <name>-vllm-logging ConfigMap
+	// +kubebuilder:validation:Enum=text;json
+	// +kubebuilder:default=text
+	// +optional
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// DownloadDir is the absolute path vLLM downloads model weights into (--download-dir)
+	// If not specified, vLLM uses the HuggingFace cache directory
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	DownloadDir string `json:"downloadDir,omitempty"`
+
+	// ModelCache mounts a shared volume as the HuggingFace cache of the model server, so weights
+	// are downloaded once and reused across pods and InferenceSchedulers
+	// +optional
+	ModelCache *ModelCacheSpec `json:"modelCache,omitempty"`
+
+	// PreloadModel downloads the model in an init container before vLLM starts, so vLLM starts
+	// from a warm cache and a rejected HF token fails the pod before the long startup. The weights
+	// are shared through ModelCache, or an emptyDir volume if no cache is configured
+	// +optional
+	PreloadModel *PreloadModelSpec `json:"preloadModel,omitempty"`
+
+	// FSGroup is the supplemental group applied to the model server pod's volumes, so a non-root
+	// vLLM process can write to a mounted model cache. The restricted Pod Security Standard allows
+	// any fsGroup; on OpenShift the value must fall within the namespace's allocated group range
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// PodSecurityContext replaces the default security context of the model server pod, which sets
+	// the RuntimeDefault seccomp profile and runs as non-root user and group 1000, with fsGroup 1000
+	// unless FSGroup is set. FSGroup is still applied if this does not set one
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// SecurityContext replaces the default security context of the vLLM container, which disallows
+	// privilege escalation, drops all capabilities and sets readOnlyRootFilesystem. With
+	// readOnlyRootFilesystem, writable emptyDir volumes are mounted at /tmp and at a HOME directory
+	// for vLLM's caches
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// TerminationGracePeriodSeconds is how long a deleted model server pod may take to finish its
+	// in-flight requests before it is killed. Defaults to 60
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// PreStopSleepSeconds delays the shutdown of a deleted model server pod, so the gateway stops
+	// routing to it before vLLM stops accepting requests. The sleep counts against
+	// TerminationGracePeriodSeconds and must be shorter than it
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PreStopSleepSeconds *int32 `json:"preStopSleepSeconds,omitempty"`
+
 	// HFTokenSecretName is the name of the secret containing HuggingFace token
-	// +kubebuilder:validation:Required
-	HFTokenSecretName string `json:"hfTokenSecretName"`
+	// If not specified, HF_TOKEN is not set, which suits public models and local model paths.
+	// The secret reference is optional, so a missing secret does not keep the pods from starting
+	// +optional
+	HFTokenSecretName string `json:"hfTokenSecretName,omitempty"`
+
+	// AdditionalTokenSecrets injects further token environment variables into the model server,
+	// e.g. when a gated base model and its adapters come from repos needing different tokens
+	// +listType=map
+	// +listMapKey=envName
+	// +optional
+	AdditionalTokenSecrets []TokenSecretRef `json:"additionalTokenSecrets,omitempty"`
 
 	// Port is the HTTP port for the model server
 	// +kubebuilder:default=8000
 	Port int32 `json:"port,omitempty"`
 
+	// TargetPort is the pod port the InferencePool and model server Service send traffic to,
+	// e.g. the listen port of a rate-limiting or token-counting sidecar proxy in front of vLLM
+	// If not specified, defaults to Port
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	TargetPort *int32 `json:"targetPort,omitempty"`
+
+	// Service tunes the model server Service, e.g. for service mesh protocol detection
+	// +optional
+	Service *ModelServerServiceSpec `json:"service,omitempty"`
+
 	// Labels to apply to model server pods
+	// app.kubernetes.io/instance is always the InferenceScheduler name, since the Service and
+	// InferencePool select on it
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// NodeSelector constrains model server pods to matching nodes, e.g. GPU nodes
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let model server pods schedule onto tainted nodes, e.g. nvidia.com/gpu
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity sets node and pod affinity rules for model server pods
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints spread the model server replicas, e.g. across zones with the
+	// topology.kubernetes.io/zone topologyKey. Constraints without a labelSelector select the
+	// model server pods of this InferenceScheduler
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// SpreadAcrossNodes adds a kubernetes.io/hostname constraint with maxSkew 1 and
+	// whenUnsatisfiable ScheduleAnyway, so one node failure does not take down every replica.
+	// Ignored if TopologySpreadConstraints already has a kubernetes.io/hostname constraint
+	// +optional
+	SpreadAcrossNodes bool `json:"spreadAcrossNodes,omitempty"`
+
+	// Workload is the kind of workload that runs the model server. StatefulSet gives pods stable
+	// names and DNS entries through a headless Service, for tensor-parallel or cache-affinity setups
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet
+	// +kubebuilder:default=Deployment
+	// +optional
+	Workload string `json:"workload,omitempty"`
+
+	// DeploymentAnnotations are set on the model server Deployment object itself, not on its pods
+	// (e.g. argocd.argoproj.io/sync-options). Annotations managed by the operator take precedence
+	// +optional
+	DeploymentAnnotations map[string]string `json:"deploymentAnnotations,omitempty"`
+
+	// DeploymentLabels are set on the model server Deployment object itself, not on its pods or
+	// selector, so they can change without breaking the immutable selector (e.g. cost-center).
+	// Labels managed by the operator take precedence
+	// +optional
+	DeploymentLabels map[string]string `json:"deploymentLabels,omitempty"`
+
+	// Annotations are set on the model server Deployment or StatefulSet and its Services, e.g. for
+	// a service mesh. DeploymentAnnotations take precedence on the workload, and annotations
+	// managed by the operator take precedence over both
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PinnedRevision is the pod-template-hash of the model server ReplicaSet the InferencePool
+	// should route to. When set, pods from other revisions (e.g. an in-progress rollout) are
+	// excluded from the pool until this value is updated to promote the new revision.
+	// With the StatefulSet workload it is matched against the controller-revision-hash instead.
+	// If not specified, all model server pods are selected
+	// +optional
+	PinnedRevision string `json:"pinnedRevision,omitempty"`
+
+	// PauseRollout sets Paused on the model server Deployment. Spec changes are still applied to the
+	// Deployment but no new pods are rolled out until it is unpaused. Only this Deployment is affected;
+	// the operator keeps reconciling every other resource of the InferenceScheduler.
+	// A StatefulSet cannot be paused, its rolling update partition is set to the replica count instead
+	// +optional
+	PauseRollout bool `json:"pauseRollout,omitempty"`
+
+	// ReadinessDeadline is how long the model server may take to become ready (e.g. "15m")
+	// Once exceeded, the Degraded condition is set and readiness is rechecked less often
+	// +optional
+	ReadinessDeadline *metav1.Duration `json:"readinessDeadline,omitempty"`
+
+	// StartupProbe tunes the probe on /health that holds off liveness checks while vLLM loads the model
+	// Defaults allow about 15 minutes for large models to load
+	// +optional
+	StartupProbe *ProbeSpec `json:"startupProbe,omitempty"`
+
+	// LivenessProbe tunes the probe on /health that restarts a hung vLLM container
+	// +optional
+	LivenessProbe *ProbeSpec `json:"livenessProbe,omitempty"`
+
+	// ReadinessProbe tunes the probe on /health that keeps a pod out of the InferencePool until
+	// vLLM can serve requests. It only starts once the startup probe has succeeded
+	// +optional
+	ReadinessProbe *ProbeSpec `json:"readinessProbe,omitempty"`
+
+	// Warmup holds a model server pod back from Ready, and so from the InferencePool, until it has
+	// completed one inference request. The readiness probe sends the request once, after /health
+	// passes, which adds the time of one completion to pod startup. Requires python3 in the image
+	// +optional
+	Warmup *WarmupSpec `json:"warmup,omitempty"`
+
+	// InitContainers run before the model server container starts (e.g. to pre-fetch weights)
+	// CPU and memory left unset default like those of Sidecars
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Sidecars are additional containers run alongside the model server container in each pod
+	// (e.g. a rate-limiting proxy, see TargetPort). CPU and memory left unset default to 50m/64Mi
+	// requests and 500m/512Mi limits; sidecars should not request GPUs
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+}
+
+// ExtraModelServerSpec defines an additional model server of an InferenceScheduler. Autoscaling,
+// ReplicasFrom, the PodDisruptionBudget and PinnedRevision of ModelServer only apply to the
+// primary model server
+type ExtraModelServerSpec struct {
+	// Name identifies the model server. Its workload and Service are named <scheduler>-<name>-vllm
+	// and its pods are labeled llm.llm-d.io/model-server=<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+
+	// ModelName is the HuggingFace model ID to serve. It must differ from the model of every
+	// other model server of the InferenceScheduler
+	// +kubebuilder:validation:MinLength=1
+	ModelName string `json:"modelName"`
+
+	// Replicas of this model server. Defaults to 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Image overrides the model server image
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources override the model server resources
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// ExtraArgs replace the extraArgs of ModelServer
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+
+	// ExtraEnv replaces the extraEnv of ModelServer
+	// +optional
+	ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+}
+
+// ModelServerServiceSpec defines the port of the model server Service
+type ModelServerServiceSpec struct {
+	// PortName is the name of the Service port. Service meshes such as Istio detect the protocol
+	// from a prefix like "http-"
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:default=http
+	// +optional
+	PortName string `json:"portName,omitempty"`
+
+	// AppProtocol is the application protocol of the Service port, e.g. "http" or "kubernetes.io/h2c"
+	// If not specified, no appProtocol is set
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	AppProtocol *string `json:"appProtocol,omitempty"`
+
+	// Type is the Kubernetes Service type. NodePort and LoadBalancer give direct access to the
+	// model server from outside the cluster, bypassing the Gateway and the EPP, e.g. for debugging
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +kubebuilder:default=ClusterIP
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// NodePort is the node port of the model server Service. Requires a NodePort or LoadBalancer
+	// Type. If not specified, Kubernetes allocates one. Extra model servers always get an
+	// allocated node port, as one node port cannot be shared between Services
+	// +kubebuilder:validation:Minimum=30000
+	// +kubebuilder:validation:Maximum=32767
+	// +optional
+	NodePort *int32 `json:"nodePort,omitempty"`
+}
+
+// ModelCacheSpec defines the volume holding the HuggingFace cache. Exactly one source must be set
+type ModelCacheSpec struct {
+	// Claim provisions a PersistentVolumeClaim named <InferenceScheduler-name>-model-cache,
+	// which is deleted with the InferenceScheduler
+	// +optional
+	Claim *ModelCacheClaimSpec `json:"claim,omitempty"`
+
+	// PersistentVolumeClaim uses an existing claim, which must support ReadWriteMany for more than one replica
+	// +optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// NFS mounts an NFS export
+	// +optional
+	NFS *corev1.NFSVolumeSource `json:"nfs,omitempty"`
+
+	// CSI mounts an inline CSI volume
+	// +optional
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+
+	// MountPath is where the cache is mounted in the model server container. HF_HOME is set to it
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:default="/root/.cache/huggingface"
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// PreloadModelSpec defines the init container that downloads the model
+type PreloadModelSpec struct {
+	// Image of the init container. It must provide huggingface-cli
+	// If not specified, the model server image is used, which is already pulled on the node
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources of the init container. CPU and memory left unset default like those of the vLLM
+	// container, so a namespace ResourceQuota does not reject the model server pods
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// ModelCacheClaimSpec defines the PersistentVolumeClaim provisioned for the model cache
+type ModelCacheClaimSpec struct {
+	// StorageClassName is the StorageClass of the claim
+	// If not specified, the cluster's default StorageClass is used
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size is the requested storage, e.g. "100Gi". It can only grow if the StorageClass allows
+	// volume expansion
+	Size resource.Quantity `json:"size"`
+
+	// AccessMode of the claim. ReadWriteOnce and ReadWriteOncePod volumes cannot be shared, so
+	// they are only accepted for a single model server replica
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteOncePod;ReadWriteMany
+	// +kubebuilder:default=ReadWriteMany
+	// +optional
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// TokenSecretRef maps an environment variable to a key of a Secret
+type TokenSecretRef struct {
+	// EnvName is the environment variable to set; it must not be HF_TOKEN, which is set from HFTokenSecretName
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	EnvName string `json:"envName"`
+
+	// SecretName is the name of the secret containing the token
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+
+	// Key is the key of the token in the secret
+	// +kubebuilder:default=token
+	// +optional
+	Key string `json:"key,omitempty"`
 }
 
-// EndpointPickerSpec defines the EPP configuration
+// EndpointPickerSpec defines the EPP configuration.
+// The EPP binary has no gRPC keepalive flags or config keys, and the v1 InferencePool
+// endpointPickerRef has no timeout or retry fields. Keepalive and call timeouts of the gateway's
+// stream to the EPP are configured on the gateway side, see the README
 type EndpointPickerSpec struct {
 	// Image is the EPP container image
 	// +kubebuilder:default="ghcr.io/llm-d/llm-d-inference-scheduler:v0.3.2"
 	Image string `json:"image,omitempty"`
 
+	// ImagePullSecrets for the EPP image, replacing spec.imagePullSecrets
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ConfigAPIVersion is the apiVersion of the EndpointPickerConfig written to plugins.yaml.
+	// Change it together with Image when a newer EPP expects a newer config schema
+	// +kubebuilder:validation:Pattern=`^[a-z0-9.-]+/v[0-9]+((alpha|beta)[0-9]+)?$`
+	// +kubebuilder:default="inference.networking.x-k8s.io/v1alpha1"
+	// +optional
+	ConfigAPIVersion string `json:"configAPIVersion,omitempty"`
+
 	// Replicas is the number of EPP instances
 	// +kubebuilder:default=1
 	Replicas int32 `json:"replicas,omitempty"`
@@ -98,13 +627,229 @@ type EndpointPickerSpec struct {
 	// +kubebuilder:default=9002
 	GRPCPort int32 `json:"grpcPort,omitempty"`
 
+	// HealthPort is the gRPC health port of the EPP (--grpc-health-port). Must differ from
+	// GRPCPort and MetricsPort. Defaults to 9003
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HealthPort *int32 `json:"healthPort,omitempty"`
+
+	// MetricsPort is the Prometheus metrics port of the EPP (--metrics-port). Must differ from
+	// GRPCPort and HealthPort. Defaults to 9090
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	MetricsPort *int32 `json:"metricsPort,omitempty"`
+
+	// LogVerbosity is the log level of the EPP (--v). Defaults to 2
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LogVerbosity *int32 `json:"logVerbosity,omitempty"`
+
+	// ExposeHealthPort adds the gRPC health port to the EPP pod and Service
+	// Set to false if the EPP image does not serve health checks on that port
+	// +kubebuilder:default=true
+	// +optional
+	ExposeHealthPort *bool `json:"exposeHealthPort,omitempty"`
+
+	// ExposeMetricsPort adds the metrics port to the EPP pod and Service
+	// Set to false if the EPP image does not serve metrics, so monitoring does not scrape a dead port
+	// +kubebuilder:default=true
+	// +optional
+	ExposeMetricsPort *bool `json:"exposeMetricsPort,omitempty"`
+
+	// PodDisruptionBudget limits voluntary evictions of the EPP pods
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
 	// Plugins configuration for routing decisions
+	// Cannot be set together with PluginConfigMapRef
 	// +optional
 	Plugins PluginConfig `json:"plugins,omitempty"`
 
+	// PluginConfigMapRef points at a key of an existing ConfigMap holding the full
+	// EndpointPickerConfig YAML. When set it is mounted as plugins.yaml instead of the config
+	// generated from Plugins. The EPP is not restarted when the referenced ConfigMap changes
+	// +optional
+	PluginConfigMapRef *corev1.ConfigMapKeySelector `json:"pluginConfigMapRef,omitempty"`
+
 	// Resources defines resource requirements for EPP pods
+	// CPU and memory requests and limits that are not set default to 100m/256Mi and 1/1Gi,
+	// unless the operator is started with other defaults
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PodSecurityContext replaces the default security context of the EPP pod, which runs as
+	// non-root with the RuntimeDefault seccomp profile
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// SecurityContext replaces the default security context of the EPP container, which disallows
+	// privilege escalation, drops all capabilities and mounts the root filesystem read-only
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// DeploymentAnnotations are set on the EPP Deployment object itself, not on its pods
+	// Annotations managed by the operator take precedence
+	// +optional
+	DeploymentAnnotations map[string]string `json:"deploymentAnnotations,omitempty"`
+
+	// DeploymentLabels are set on the EPP Deployment object itself, not on its pods or selector
+	// Labels managed by the operator take precedence
+	// +optional
+	DeploymentLabels map[string]string `json:"deploymentLabels,omitempty"`
+
+	// Annotations are set on the EPP Deployment and Service. DeploymentAnnotations take precedence
+	// on the Deployment, and annotations managed by the operator take precedence over both
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// StartupProbe tunes the gRPC health probe run while the EPP starts
+	// The EPP starts within seconds, so the default allows 30 seconds
+	// +optional
+	StartupProbe *ProbeSpec `json:"startupProbe,omitempty"`
+
+	// LivenessProbe tunes the gRPC health probe that restarts a hung EPP container
+	// EPP probes are only added when ExposeHealthPort is enabled
+	// +optional
+	LivenessProbe *ProbeSpec `json:"livenessProbe,omitempty"`
+
+	// PauseRollout sets Paused on the EPP Deployment. Spec changes are still applied to the
+	// Deployment but no new pods are rolled out until it is unpaused
+	// +optional
+	PauseRollout bool `json:"pauseRollout,omitempty"`
+
+	// TopologySpreadConstraints spread the EPP replicas, e.g. across zones with the
+	// topology.kubernetes.io/zone topologyKey. Constraints without a labelSelector select the EPP pods
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// NodePort makes the EPP Service a NodePort Service, for gRPC access from outside the cluster
+	// while debugging. Not intended for production, where only the Gateway talks to the EPP.
+	// If not specified, the EPP Service is ClusterIP
+	// +optional
+	NodePort *EPPNodePortSpec `json:"nodePort,omitempty"`
+
+	// FailureMode is the failureMode of the InferencePool endpointPickerRef. With FailOpen the
+	// gateway keeps routing requests when the EPP is unavailable, bypassing its load-aware
+	// picking. With FailClose those requests fail instead
+	// +kubebuilder:validation:Enum=FailOpen;FailClose
+	// +kubebuilder:default=FailOpen
+	// +optional
+	FailureMode string `json:"failureMode,omitempty"`
+
+	// RBAC grants the EPP the extra permissions newer llm-d scheduler versions need. If not
+	// specified, the EPP may only read pods and InferencePools
+	// +optional
+	RBAC *EPPRBACSpec `json:"rbac,omitempty"`
+
+	// Observability configures the EPP to export traces to an OpenTelemetry collector over OTLP
+	// If not specified, the EPP is not configured for export
+	// +optional
+	Observability *EPPObservabilitySpec `json:"observability,omitempty"`
+}
+
+// EPPObservabilitySpec defines the OTLP exporter of the EPP, set through the standard
+// OpenTelemetry environment variables
+type EPPObservabilitySpec struct {
+	// OTLPEndpoint is the collector endpoint, e.g. http://otel-collector.observability:4317
+	// Rendered as OTEL_EXPORTER_OTLP_ENDPOINT
+	// +kubebuilder:validation:Pattern=`^https?://`
+	OTLPEndpoint string `json:"otlpEndpoint"`
+
+	// Protocol is the OTLP transport. Rendered as OTEL_EXPORTER_OTLP_PROTOCOL
+	// +kubebuilder:validation:Enum=grpc;http/protobuf
+	// +kubebuilder:default=grpc
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// HeadersSecretRef selects a secret key holding the exporter headers, e.g. for authentication,
+	// as comma-separated key=value pairs. Rendered as OTEL_EXPORTER_OTLP_HEADERS
+	// +optional
+	HeadersSecretRef *corev1.SecretKeySelector `json:"headersSecretRef,omitempty"`
+}
+
+// EPPRBACSpec defines the optional permissions of the EPP Role
+type EPPRBACSpec struct {
+	// LeaderElection allows the EPP to manage coordination.k8s.io leases, for EPP versions that
+	// elect a leader among their replicas
+	// +optional
+	LeaderElection bool `json:"leaderElection,omitempty"`
+
+	// EndpointSlices allows the EPP to read discovery.k8s.io endpointslices
+	// +optional
+	EndpointSlices bool `json:"endpointSlices,omitempty"`
+}
+
+// EPPNodePortSpec defines the node ports of the EPP Service. Ports left unset are allocated by
+// Kubernetes. The ranges match the default --service-node-port-range of the API server
+type EPPNodePortSpec struct {
+	// GRPC is the node port for the EPP gRPC port
+	// +kubebuilder:validation:Minimum=30000
+	// +kubebuilder:validation:Maximum=32767
+	// +optional
+	GRPC *int32 `json:"grpc,omitempty"`
+
+	// Health is the node port for the EPP gRPC health port. Requires ExposeHealthPort
+	// +kubebuilder:validation:Minimum=30000
+	// +kubebuilder:validation:Maximum=32767
+	// +optional
+	Health *int32 `json:"health,omitempty"`
+}
+
+// WarmupSpec defines the inference request that warms up a model server pod
+type WarmupSpec struct {
+	// Path of the completions endpoint the warmup request is sent to
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:default="/v1/completions"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Prompt of the warmup request. The model generates a single token
+	// +kubebuilder:default="Hello"
+	// +optional
+	Prompt string `json:"prompt,omitempty"`
+
+	// TimeoutSeconds is how long the warmup request may take before the attempt fails and is retried
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// ProbeSpec tunes a container probe. The probe handler is fixed per component,
+// and unset fields use the component's defaults
+type ProbeSpec struct {
+	// Disabled removes the probe from the container
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// InitialDelaySeconds is the delay before the probe first runs
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// PeriodSeconds is how often the probe runs
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds is how long a single probe may take
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures before the probe is considered failed
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// Scheme of the model server's HTTP health probes; set HTTPS when vLLM serves TLS.
+	// Ignored by the EPP's gRPC probes
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +kubebuilder:default=HTTP
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
 }
 
 // PluginConfig defines the plugin configuration for EPP
@@ -120,6 +865,40 @@ type PluginConfig struct {
 	// KVCacheUtilizationScorer configuration
 	// +optional
 	KVCacheUtilizationScorer *ScorerPlugin `json:"kvCacheUtilizationScorer,omitempty"`
+
+	// SessionAffinityScorer configuration
+	// Routes requests of the same session back to the endpoint that served it before
+	// +optional
+	SessionAffinityScorer *ScorerPlugin `json:"sessionAffinityScorer,omitempty"`
+
+	// LoraAffinityScorer configuration
+	// Favours endpoints that already have the requested LoRA adapter loaded
+	// +optional
+	LoraAffinityScorer *ScorerPlugin `json:"loraAffinityScorer,omitempty"`
+
+	// AdditionalScorers are rendered into plugins.yaml as-is, after the scorers above
+	// Use it for EPP scorers that have no typed field, so new scorers need no operator change
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	AdditionalScorers []AdditionalScorerPlugin `json:"additionalScorers,omitempty"`
+}
+
+// AdditionalScorerPlugin defines a scorer plugin the operator has no typed field for
+type AdditionalScorerPlugin struct {
+	// Type is the plugin type registered in the EPP (e.g., "queue-scorer")
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +required
+	Type string `json:"type"`
+
+	// Weight is the weight for this scorer
+	// +kubebuilder:default=1.0
+	// +kubebuilder:validation:Type=number
+	Weight *float64 `json:"weight,omitempty"`
+
+	// Parameters are plugin-specific parameters, passed to the EPP unchecked
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // ScorerPlugin defines a scorer plugin configuration
@@ -133,7 +912,23 @@ type ScorerPlugin struct {
 	// +kubebuilder:validation:Type=number
 	Weight *float64 `json:"weight,omitempty"`
 
+	// QueueThreshold is the queue length at which the load-aware scorer considers an endpoint saturated
+	// Takes precedence over Parameters["queueThreshold"]. Defaults to 128
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	QueueThreshold *int32 `json:"queueThreshold,omitempty"`
+
+	// CacheHitBonus is the score bonus the prefix cache scorer gives endpoints with a cache hit
+	// Takes precedence over Parameters["cacheHitBonus"]. Defaults to 1.0
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Type=number
+	// +optional
+	CacheHitBonus *float64 `json:"cacheHitBonus,omitempty"`
+
 	// Parameters are plugin-specific parameters
+	// Use it for parameters that have no typed field above. The validating webhook only accepts
+	// queueThreshold (an integer) for the load-aware scorer and cacheHitBonus (a number) for the
+	// prefix cache scorer
 	// +optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 }
@@ -146,10 +941,25 @@ type GatewaySpec struct {
 	// +kubebuilder:default="kgateway"
 	ClassName string `json:"className,omitempty"`
 
+	// ExistingGatewayRef attaches the HTTPRoutes to a Gateway managed outside the operator, e.g. a
+	// shared Gateway owned by a platform team. No Gateway is created, so TLS, AllowedRoutes and a
+	// ClassName other than the default must not be set. SectionName still selects the listener to
+	// attach to
+	// +optional
+	ExistingGatewayRef *GatewayReference `json:"existingGatewayRef,omitempty"`
+
 	// ListenerPort is the HTTP listener port
 	// +kubebuilder:default=80
 	ListenerPort int32 `json:"listenerPort,omitempty"`
 
+	// SectionName names the Gateway listener and is set as the sectionName of the HTTPRoute
+	// parentRefs, so the routes attach to that listener only. If not specified, the listener is
+	// named "http" and the routes attach to all listeners of the Gateway
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+
 	// ServiceType is the Kubernetes Service type (ClusterIP, LoadBalancer, NodePort)
 	// +kubebuilder:validation:Enum=ClusterIP;LoadBalancer;NodePort
 	// +kubebuilder:default="ClusterIP"
@@ -159,6 +969,138 @@ type GatewaySpec struct {
 	// If not specified, defaults to <InferenceScheduler-name>-gateway
 	// +optional
 	Name string `json:"name,omitempty"`
+
+	// HealthCheckRoute creates an additional HTTPRoute that sends /health directly to the
+	// model server Service, bypassing the InferencePool
+	// +optional
+	HealthCheckRoute bool `json:"healthCheckRoute,omitempty"`
+
+	// AllowedRoutes controls which namespaces may attach routes to the Gateway listener
+	// If not specified, only routes in the InferenceScheduler's namespace are allowed
+	// +optional
+	AllowedRoutes *AllowedRoutesSpec `json:"allowedRoutes,omitempty"`
+
+	// BackendRef tunes the InferencePool backendRef of the HTTPRoute
+	// +optional
+	BackendRef *RouteBackendRefSpec `json:"backendRef,omitempty"`
+
+	// Route sets the hostnames and paths the HTTPRoute matches, so several InferenceSchedulers
+	// can share a Gateway. If not specified, the route matches the /v1/ prefix on any hostname
+	// +optional
+	Route *RouteSpec `json:"route,omitempty"`
+
+	// TLS terminates HTTPS on the Gateway. The HTTPRoutes attach to the HTTPS listener and the
+	// HTTP listener is only kept if HTTPRedirect is set
+	// +optional
+	TLS *GatewayTLSSpec `json:"tls,omitempty"`
+
+	// Annotations are set on the created Gateway, e.g. for cloud load balancer or service mesh
+	// configuration read by the Gateway implementation. Not set on an ExistingGatewayRef Gateway.
+	// Annotations managed by the operator take precedence
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayTLSSpec defines the HTTPS listener of the Gateway
+type GatewayTLSSpec struct {
+	// CertificateSecretName is the kubernetes.io/tls Secret, in the InferenceScheduler's namespace,
+	// holding the certificate and key
+	// +kubebuilder:validation:MinLength=1
+	CertificateSecretName string `json:"certificateSecretName"`
+
+	// Hostname restricts the HTTPS listener to a hostname, e.g. for SNI
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// Port is the HTTPS listener port
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=443
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// ListenerName names the HTTPS listener and is the sectionName of the HTTPRoute parentRefs
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:default=https
+	// +optional
+	ListenerName string `json:"listenerName,omitempty"`
+
+	// HTTPRedirect keeps the HTTP listener and attaches an HTTPRoute <name>-https-redirect to it
+	// that redirects every request to HTTPS
+	// +optional
+	HTTPRedirect bool `json:"httpRedirect,omitempty"`
+}
+
+// GatewayReference references a Gateway managed outside the operator
+type GatewayReference struct {
+	// Name of the Gateway
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. If not specified, the InferenceScheduler's namespace is used.
+	// The Gateway's listeners must allow routes from the InferenceScheduler's namespace
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RouteSpec defines the requests the HTTPRoute matches
+type RouteSpec struct {
+	// Hostnames the HTTPRoute matches, e.g. qwen.example.com or *.example.com. The health
+	// check and HTTPS redirect routes match the same hostnames
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:Pattern=`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +kubebuilder:validation:items:MaxLength=253
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Paths the HTTPRoute matches. If not specified, the route matches the /v1/ prefix
+	// +kubebuilder:validation:MaxItems=64
+	// +optional
+	Paths []RoutePathMatch `json:"paths,omitempty"`
+}
+
+// RoutePathMatch defines a path match of the HTTPRoute
+type RoutePathMatch struct {
+	// Type of the match
+	// +kubebuilder:validation:Enum=PathPrefix;Exact;RegularExpression
+	// +kubebuilder:default=PathPrefix
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Value is the path, e.g. /qwen/v1/
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	Value string `json:"value"`
+}
+
+// RouteBackendRefSpec defines the port and weight of the HTTPRoute's InferencePool backendRef
+type RouteBackendRefSpec struct {
+	// Port of the backendRef. If not specified, the InferencePool's target port is used
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// Weight of the backendRef. If not specified, the Gateway API default of 1 applies
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// AllowedRoutesSpec defines the namespaces allowed to attach routes to the Gateway listener
+type AllowedRoutesSpec struct {
+	// From is Same for the Gateway's namespace, All for every namespace, or Selector for the
+	// namespaces matching Selector
+	// +kubebuilder:validation:Enum=Same;All;Selector
+	// +kubebuilder:default=Same
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// Selector selects the allowed namespaces by label. Required when From is Selector
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
 // InferenceSchedulerStatus defines the observed state of InferenceScheduler
@@ -171,7 +1113,17 @@ type InferenceSchedulerStatus struct {
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
-	// ModelServerReplicas is the current number of model server replicas
+	// ObservedGeneration is the generation of the spec that was last reconciled successfully.
+	// Phase reflects an older spec while it is less than metadata.generation
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// TimeToReady is how long the InferenceScheduler took from its creation to the first time it
+	// was Ready. It is not changed when the InferenceScheduler becomes Ready again later
+	// +optional
+	TimeToReady *metav1.Duration `json:"timeToReady,omitempty"`
+
+	// ModelServerReplicas is the number of ready model server replicas
 	// +optional
 	ModelServerReplicas int32 `json:"modelServerReplicas,omitempty"`
 
@@ -183,6 +1135,16 @@ type InferenceSchedulerStatus struct {
 	// +optional
 	GatewayReady bool `json:"gatewayReady,omitempty"`
 
+	// GatewayAddress is the first address the gateway implementation assigned to the Gateway.
+	// Empty until an address is assigned
+	// +optional
+	GatewayAddress string `json:"gatewayAddress,omitempty"`
+
+	// EndpointURL is the OpenAI-compatible base URL to send inference requests to, built from
+	// GatewayAddress and the listener port. Empty until the Gateway has an address
+	// +optional
+	EndpointURL string `json:"endpointURL,omitempty"`
+
 	// InferencePoolReady indicates if the InferencePool is ready
 	// +optional
 	InferencePoolReady bool `json:"inferencePoolReady,omitempty"`
@@ -194,6 +1156,27 @@ type InferenceSchedulerStatus struct {
 	// PrerequisiteMessage provides details about missing prerequisites
 	// +optional
 	PrerequisiteMessage string `json:"prerequisiteMessage,omitempty"`
+
+	// MissingPrerequisites lists the prerequisites that were not found during the last validation
+	// (GatewayAPI, HTTPRoute, GatewayAPIInferenceExtension, GatewayClassCRD, GatewayClass)
+	// +optional
+	MissingPrerequisites []string `json:"missingPrerequisites,omitempty"`
+
+	// ActivePlugins lists the plugins of the generated EPP config as type=weight, e.g.
+	// prefix-cache-scorer=2.0. Empty when the config is read from PluginConfigMapRef
+	// +optional
+	ActivePlugins []string `json:"activePlugins,omitempty"`
+
+	// PluginConfigHash is the hash of the generated EPP config, matching the
+	// llm.llm-d.io/config-hash annotation of the EPP pods
+	// +optional
+	PluginConfigHash string `json:"pluginConfigHash,omitempty"`
+
+	// Plan lists the changes the operator would make to the children, one per created, updated or
+	// deleted child, while the llm.llm-d.io/dry-run annotation is "true". Cleared once the
+	// annotation is removed
+	// +optional
+	Plan []string `json:"plan,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -202,6 +1185,8 @@ type InferenceSchedulerStatus struct {
 // +kubebuilder:printcolumn:name="Model",type=string,JSONPath=`.spec.modelServer.modelName`
 // +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.spec.modelServer.replicas`
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.status.gatewayAddress`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // InferenceScheduler is the Schema for the inferenceschedulers API
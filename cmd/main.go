@@ -18,16 +18,25 @@ package main
 
 import (
 	"crypto/tls"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -39,6 +48,7 @@ import (
 
 	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
 	"github.com/aneeshkp/inference-scheduler-operator/internal/controller"
+	webhookv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -63,6 +73,11 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var finalizerName string
+	var modelCatalog string
+	var prerequisiteRecheckInterval time.Duration
+	var maxPrerequisiteRechecks int
+	resourceDefaults := controller.DefaultResources()
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +96,30 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&finalizerName, "finalizer-name", controller.DefaultFinalizerName,
+		"The finalizer added to InferenceSchedulers. Objects carrying the default finalizer are migrated to it.")
+	flag.StringVar(&modelCatalog, "model-catalog", "",
+		"The <namespace>/<name> of a ConfigMap listing the models served by all InferenceSchedulers. "+
+			"Leave empty to not maintain a model catalog.")
+	flag.DurationVar(&prerequisiteRecheckInterval, "prerequisite-recheck-interval",
+		controller.DefaultPrerequisiteRecheckInterval,
+		"How long to wait before checking missing prerequisites again. The interval doubles for every recheck.")
+	flag.IntVar(&maxPrerequisiteRechecks, "max-prerequisite-rechecks", 0,
+		"Stop rechecking missing prerequisites after this many rechecks and wait for a spec change or a CRD "+
+			"installation instead. 0 rechecks forever.")
+	flag.Var(resourceListFlag{&resourceDefaults.ModelServer.Requests}, "model-server-default-requests",
+		"The requests of the vLLM and model preload containers that leave them unset, e.g. cpu=500m,memory=2Gi.")
+	flag.Var(resourceListFlag{&resourceDefaults.ModelServer.Limits}, "model-server-default-limits",
+		"The limits of the vLLM and model preload containers that leave them unset, e.g. cpu=8,memory=64Gi. "+
+			"Set them if a ResourceQuota covers limits; a limit below what a model needs gets it OOM-killed.")
+	flag.Var(resourceListFlag{&resourceDefaults.EndpointPicker.Requests}, "epp-default-requests",
+		"The requests of the EPP container if it leaves them unset.")
+	flag.Var(resourceListFlag{&resourceDefaults.EndpointPicker.Limits}, "epp-default-limits",
+		"The limits of the EPP container if it leaves them unset.")
+	flag.Var(resourceListFlag{&resourceDefaults.Containers.Requests}, "container-default-requests",
+		"The requests of the model server init containers and sidecars that leave them unset.")
+	flag.Var(resourceListFlag{&resourceDefaults.Containers.Limits}, "container-default-limits",
+		"The limits of the model server init containers and sidecars that leave them unset.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -89,6 +128,21 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if errs := validation.IsQualifiedName(finalizerName); len(errs) > 0 {
+		setupLog.Error(errors.New(strings.Join(errs, "; ")), "invalid finalizer name", "finalizer", finalizerName)
+		os.Exit(1)
+	}
+
+	var modelCatalogKey types.NamespacedName
+	if modelCatalog != "" {
+		namespace, name, ok := strings.Cut(modelCatalog, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(errors.New("expected <namespace>/<name>"), "invalid model catalog", "modelCatalog", modelCatalog)
+			os.Exit(1)
+		}
+		modelCatalogKey = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -203,12 +257,25 @@ func main() {
 	}
 
 	if err := (&controller.InferenceSchedulerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                      mgr.GetClient(),
+		Scheme:                      mgr.GetScheme(),
+		APIReader:                   mgr.GetAPIReader(),
+		FinalizerName:               finalizerName,
+		ModelCatalog:                modelCatalogKey,
+		PrerequisiteRecheckInterval: prerequisiteRecheckInterval,
+		MaxPrerequisiteRechecks:     maxPrerequisiteRechecks,
+		ResourceDefaults:            &resourceDefaults,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "InferenceScheduler")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupInferenceSchedulerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "InferenceScheduler")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -235,6 +302,11 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// Report not-ready until the informer caches used by the reconciler have synced
+	if err := mgr.AddReadyzCheck("informer-sync", controller.InformerSyncCheck(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to set up informer sync check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -242,3 +314,41 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// resourceListFlag is a flag holding a ResourceList in the form cpu=500m,memory=2Gi. An empty
+// value sets no defaults
+type resourceListFlag struct {
+	list *corev1.ResourceList
+}
+
+func (f resourceListFlag) String() string {
+	if f.list == nil {
+		return ""
+	}
+	entries := make([]string, 0, len(*f.list))
+	for name, quantity := range *f.list {
+		entries = append(entries, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func (f resourceListFlag) Set(value string) error {
+	list := corev1.ResourceList{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, quantity, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("expected <resource>=<quantity>, got %q", entry)
+		}
+		parsed, err := resource.ParseQuantity(quantity)
+		if err != nil {
+			return fmt.Errorf("invalid quantity for %s: %w", name, err)
+		}
+		list[corev1.ResourceName(name)] = parsed
+	}
+	*f.list = list
+	return nil
+}
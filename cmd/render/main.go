@@ -0,0 +1,122 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// render prints the manifests the operator would create for an InferenceScheduler, without
+// connecting to a cluster.
+//
+// Defaults declared in the CRD schema are applied first, as the API server would.
+//
+//	go run ./cmd/render -f inferencescheduler.yaml > manifests.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+	"github.com/aneeshkp/inference-scheduler-operator/internal/controller"
+)
+
+func main() {
+	var file, namespace, crdFile string
+	flag.StringVar(&file, "f", "-", "The InferenceScheduler manifest to render, or - to read from stdin.")
+	flag.StringVar(&crdFile, "crd", "config/crd/bases/llm.llm-d.io_inferenceschedulers.yaml",
+		"The InferenceScheduler CRD whose schema defaults are applied before rendering.")
+	flag.StringVar(&namespace, "namespace", "default",
+		"The namespace to render into when the manifest does not set one.")
+	flag.Parse()
+
+	if err := run(file, namespace, crdFile, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "render: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(file, namespace, crdFile string, out io.Writer) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Validate field names against the Go types before defaulting
+	infScheduler := &llmv1alpha1.InferenceScheduler{}
+	if err := yaml.UnmarshalStrict(data, infScheduler); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	schema, err := loadSchema(crdFile)
+	if err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(infScheduler)
+	if err != nil {
+		return err
+	}
+	defaulting.Default(content, schema)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, infScheduler); err != nil {
+		return err
+	}
+	if infScheduler.Namespace == "" {
+		infScheduler.Namespace = namespace
+	}
+
+	manifests, err := controller.RenderManifests(infScheduler)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(manifests)
+	return err
+}
+
+// loadSchema returns the structural schema of the served version of the CRD
+func loadSchema(crdFile string) (*structuralschema.Structural, error) {
+	data, err := os.ReadFile(crdFile)
+	if err != nil {
+		return nil, err
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", crdFile, err)
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if version.Name != llmv1alpha1.GroupVersion.Version || version.Schema == nil {
+			continue
+		}
+		internal := &apiextensions.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+			version.Schema.OpenAPIV3Schema, internal, nil); err != nil {
+			return nil, err
+		}
+		return structuralschema.NewStructural(internal)
+	}
+	return nil, fmt.Errorf("%s has no schema for version %s", crdFile, llmv1alpha1.GroupVersion.Version)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxRequeueBackoff caps the backed off requeues, so a scheduler that waits for a long time is
+// still checked every few minutes
+const maxRequeueBackoff = 5 * time.Minute
+
+// Reasons for a backed off requeue. Waiting for something else starts over at the base interval
+const (
+	waitPrerequisites    = "Prerequisites"
+	waitModelServerReady = "ModelServerReady"
+	waitEPPReady         = "EPPReady"
+	waitPoolAccepted     = "InferencePoolAccepted"
+)
+
+// requeueBackoff doubles the requeue interval of an InferenceScheduler for every consecutive
+// reconcile that ends waiting for the same thing. The zero value is ready to use
+type requeueBackoff struct {
+	mu    sync.Mutex
+	waits map[types.NamespacedName]backoffWait
+}
+
+// backoffWait is what an InferenceScheduler is waiting for and how often it was requeued for it.
+// attempts stops growing once the interval reaches maxRequeueBackoff, requeues does not
+type backoffWait struct {
+	reason   string
+	attempts int
+	requeues int
+}
+
+// next returns the interval to requeue after for reason, starting at base and doubling up to
+// maxRequeueBackoff
+func (b *requeueBackoff) next(key types.NamespacedName, reason string, base time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.waits == nil {
+		b.waits = map[types.NamespacedName]backoffWait{}
+	}
+	wait := b.waits[key]
+	if wait.reason != reason {
+		wait = backoffWait{reason: reason}
+	}
+
+	delay := base
+	for i := 0; i < wait.attempts && delay < maxRequeueBackoff; i++ {
+		delay *= 2
+	}
+	if delay >= maxRequeueBackoff {
+		delay = maxRequeueBackoff
+	} else {
+		wait.attempts++
+	}
+	wait.requeues++
+	b.waits[key] = wait
+	return delay
+}
+
+// requeues returns how often the InferenceScheduler was requeued while waiting for reason
+func (b *requeueBackoff) requeues(key types.NamespacedName, reason string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if wait := b.waits[key]; wait.reason == reason {
+		return wait.requeues
+	}
+	return 0
+}
+
+// reset starts the backoff over once the InferenceScheduler is no longer waiting for reason
+func (b *requeueBackoff) reset(key types.NamespacedName, reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.waits[key].reason == reason {
+		delete(b.waits, key)
+	}
+}
+
+// forget drops the backoff of a deleted InferenceScheduler
+func (b *requeueBackoff) forget(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.waits, key)
+}
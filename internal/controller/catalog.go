@@ -0,0 +1,149 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// catalogEntry describes the model served by one InferenceScheduler in the model catalog
+type catalogEntry struct {
+	Model            string   `json:"model"`
+	ServedModelNames []string `json:"servedModelNames,omitempty"`
+	Endpoint         string   `json:"endpoint,omitempty"`
+	Phase            string   `json:"phase"`
+	Ready            bool     `json:"ready"`
+}
+
+// catalogKey is the ConfigMap key of an InferenceScheduler's catalog entry
+func catalogKey(key types.NamespacedName) string {
+	return fmt.Sprintf("%s.%s", key.Namespace, key.Name)
+}
+
+// buildCatalogEntry describes an InferenceScheduler for the model catalog. The endpoint is only
+// known once the gateway implementation has assigned the Gateway an address
+func buildCatalogEntry(infScheduler *llmv1alpha1.InferenceScheduler, gatewayAddress string) catalogEntry {
+	entry := catalogEntry{
+		Model:            infScheduler.Spec.ModelServer.ModelName,
+		ServedModelNames: infScheduler.Spec.ModelServer.ServedModelNames,
+		Phase:            infScheduler.Status.Phase,
+		Ready:            infScheduler.Status.Phase == "Ready",
+	}
+	entry.Endpoint = gatewayEndpointURL(infScheduler, gatewayAddress)
+	return entry
+}
+
+// gatewayEndpointURL returns the OpenAI-compatible base URL served on the Gateway's listener, or
+// "" if the Gateway has no address yet
+func gatewayEndpointURL(infScheduler *llmv1alpha1.InferenceScheduler, gatewayAddress string) string {
+	if gatewayAddress == "" {
+		return ""
+	}
+	listenerPort := getDefaultInt32(&infScheduler.Spec.Gateway.ListenerPort, defaultGatewayPort)
+	scheme := "http"
+	if tls := infScheduler.Spec.Gateway.TLS; tls != nil {
+		scheme = "https"
+		listenerPort = getDefaultInt32(&tls.Port, defaultGatewayTLSPort)
+	}
+	return fmt.Sprintf("%s://%s:%d/v1", scheme, gatewayAddress, listenerPort)
+}
+
+// gatewayAddress returns the first address reported in the Gateway's status, or "" if it has none yet
+func (r *InferenceSchedulerReconciler) gatewayAddress(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) string {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(r.buildGateway(infScheduler).GroupVersionKind())
+	if err := r.Get(ctx, gatewayKey(infScheduler), existing); err != nil {
+		return ""
+	}
+
+	addresses, _, _ := unstructured.NestedSlice(existing.Object, "status", "addresses")
+	for _, address := range addresses {
+		if addressMap, ok := address.(map[string]interface{}); ok {
+			if value, ok := addressMap["value"].(string); ok && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// updateModelCatalog records the InferenceScheduler in the model catalog ConfigMap
+func (r *InferenceSchedulerReconciler) updateModelCatalog(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) error {
+	if r.ModelCatalog.Name == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(buildCatalogEntry(infScheduler, r.gatewayAddress(ctx, infScheduler)))
+	if err != nil {
+		return err
+	}
+	return r.setModelCatalogEntry(ctx, catalogKey(client.ObjectKeyFromObject(infScheduler)), string(data))
+}
+
+// removeFromModelCatalog deletes the entry of a deleted InferenceScheduler from the model catalog
+func (r *InferenceSchedulerReconciler) removeFromModelCatalog(ctx context.Context, key types.NamespacedName) error {
+	if r.ModelCatalog.Name == "" {
+		return nil
+	}
+	return r.setModelCatalogEntry(ctx, catalogKey(key), "")
+}
+
+// setModelCatalogEntry writes one entry of the model catalog ConfigMap, creating it if needed.
+// An empty value removes the entry. Every InferenceScheduler writes to the same ConfigMap, so
+// conflicting updates are retried
+func (r *InferenceSchedulerReconciler) setModelCatalogEntry(ctx context.Context, key, value string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		catalog := &corev1.ConfigMap{}
+		if err := r.Get(ctx, r.ModelCatalog, catalog); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			if value == "" {
+				return nil
+			}
+			catalog.Name = r.ModelCatalog.Name
+			catalog.Namespace = r.ModelCatalog.Namespace
+			setManagedMetadata(catalog)
+			catalog.Data = map[string]string{key: value}
+			return r.Create(ctx, catalog)
+		}
+
+		if current, ok := catalog.Data[key]; ok == (value != "") && current == value {
+			return nil
+		}
+		if value == "" {
+			delete(catalog.Data, key)
+		} else {
+			if catalog.Data == nil {
+				catalog.Data = map[string]string{}
+			}
+			catalog.Data[key] = value
+		}
+		return r.Update(ctx, catalog)
+	})
+}
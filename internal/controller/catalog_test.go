@@ -0,0 +1,71 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+var _ = Describe("Model catalog", func() {
+	var infScheduler *llmv1alpha1.InferenceScheduler
+
+	BeforeEach(func() {
+		infScheduler = &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-scheduler",
+				Namespace: "default",
+			},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{
+					ModelName:        "Qwen/Qwen2.5-0.5B-Instruct",
+					ServedModelNames: []string{"qwen"},
+				},
+				Gateway: llmv1alpha1.GatewaySpec{
+					ListenerPort: 8080,
+				},
+			},
+		}
+	})
+
+	It("should key entries by namespace and name", func() {
+		Expect(catalogKey(types.NamespacedName{Namespace: "default", Name: "test-scheduler"})).To(Equal("default.test-scheduler"))
+	})
+
+	It("should report the endpoint once the Gateway has an address", func() {
+		infScheduler.Status.Phase = "Ready"
+
+		entry := buildCatalogEntry(infScheduler, "10.0.0.1")
+		Expect(entry.Model).To(Equal("Qwen/Qwen2.5-0.5B-Instruct"))
+		Expect(entry.ServedModelNames).To(Equal([]string{"qwen"}))
+		Expect(entry.Endpoint).To(Equal("http://10.0.0.1:8080/v1"))
+		Expect(entry.Ready).To(BeTrue())
+	})
+
+	It("should list schedulers that are not ready without an endpoint", func() {
+		infScheduler.Status.Phase = "Deploying"
+
+		entry := buildCatalogEntry(infScheduler, "")
+		Expect(entry.Endpoint).To(BeEmpty())
+		Expect(entry.Phase).To(Equal("Deploying"))
+		Expect(entry.Ready).To(BeFalse())
+	})
+})
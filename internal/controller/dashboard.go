@@ -0,0 +1,128 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// grafanaDashboardLabel makes the Grafana dashboard sidecar load the ConfigMap
+const grafanaDashboardLabel = "grafana_dashboard"
+
+// buildDashboardConfigMap creates a ConfigMap with a Grafana dashboard for the model servers and
+// the EPP of this InferenceScheduler. Returns nil unless Monitoring.Dashboard is set
+func (r *InferenceSchedulerReconciler) buildDashboardConfigMap(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.ConfigMap {
+	monitoring := infScheduler.Spec.Monitoring
+	if monitoring == nil || !monitoring.Dashboard {
+		return nil
+	}
+
+	// Prometheus Operator labels the scraped series with the namespace and Service they came from
+	services := []string{regexp.QuoteMeta(fmt.Sprintf("%s-vllm", infScheduler.Name))}
+	for _, extra := range infScheduler.Spec.ExtraModelServers {
+		services = append(services, regexp.QuoteMeta(extraModelServerName(infScheduler, extra)))
+	}
+	vllm := fmt.Sprintf(`namespace=%q,service=~%q`, infScheduler.Namespace, strings.Join(services, "|"))
+	pool := fmt.Sprintf(`namespace=%q,name=%q`, infScheduler.Namespace, fmt.Sprintf("%s-pool", infScheduler.Name))
+
+	panels := []interface{}{
+		dashboardPanel(1, "Request latency", "s", 0, 0,
+			fmt.Sprintf(`histogram_quantile(0.5, sum by (le) (rate(vllm:e2e_request_latency_seconds_bucket{%s}[5m])))`, vllm), "p50",
+			fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(vllm:e2e_request_latency_seconds_bucket{%s}[5m])))`, vllm), "p95",
+			fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(vllm:e2e_request_latency_seconds_bucket{%s}[5m])))`, vllm), "p99"),
+		dashboardPanel(2, "Queue depth", "short", 12, 0,
+			fmt.Sprintf(`sum by (pod) (vllm:num_requests_waiting{%s})`, vllm), "{{pod}}",
+			fmt.Sprintf(`inference_pool_average_queue_size{%s}`, pool), "pool average"),
+		dashboardPanel(3, "KV cache utilization", "percentunit", 0, 8,
+			fmt.Sprintf(`avg by (pod) (vllm:kv_cache_usage_perc{%s})`, vllm), "{{pod}}",
+			fmt.Sprintf(`inference_pool_average_kv_cache_utilization{%s}`, pool), "pool average"),
+		dashboardPanel(4, "Prefix cache hit rate", "percentunit", 12, 8,
+			fmt.Sprintf(`sum(rate(vllm:prefix_cache_hits_total{%s}[5m])) / sum(rate(vllm:prefix_cache_queries_total{%s}[5m]))`, vllm, vllm), "hit rate"),
+	}
+
+	// Grafana requires a uid of at most 40 characters that is unique across namespaces
+	sum := sha256.Sum256([]byte(infScheduler.Namespace + "/" + infScheduler.Name))
+	dashboard := map[string]interface{}{
+		"uid":           "inference-scheduler-" + hex.EncodeToString(sum[:])[:16],
+		"title":         fmt.Sprintf("Inference Scheduler / %s / %s", infScheduler.Namespace, infScheduler.Name),
+		"tags":          []interface{}{"inference-scheduler", "vllm", "llm-d"},
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]interface{}{"from": "now-1h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+			},
+		},
+		"panels": panels,
+	}
+	// The dashboard only holds strings, numbers and nested maps, so it always marshals
+	data, _ := json.MarshalIndent(dashboard, "", "  ")
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-dashboard", infScheduler.Name),
+			Namespace: infScheduler.Namespace,
+			Labels:    mergeStringMaps(monitoring.Labels, map[string]string{grafanaDashboardLabel: "1"}),
+		},
+		Data: map[string]string{
+			// The sidecar writes every key to one directory, so the key must not collide across namespaces
+			fmt.Sprintf("%s-%s.json", infScheduler.Namespace, infScheduler.Name): string(data),
+		},
+	}
+}
+
+// dashboardPanel returns a Grafana time series panel at the given grid position, half the
+// dashboard wide, with one target per expression and legend pair
+func dashboardPanel(id int, title, unit string, x, y int, exprsAndLegends ...string) map[string]interface{} {
+	var targets []interface{}
+	for i := 0; i+1 < len(exprsAndLegends); i += 2 {
+		targets = append(targets, map[string]interface{}{
+			"refId":        string(rune('A' + i/2)),
+			"expr":         exprsAndLegends[i],
+			"legendFormat": exprsAndLegends[i+1],
+			"datasource":   map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+		})
+	}
+	return map[string]interface{}{
+		"id":         id,
+		"type":       "timeseries",
+		"title":      title,
+		"datasource": map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+		"gridPos":    map[string]interface{}{"x": x, "y": y, "w": 12, "h": 8},
+		"fieldConfig": map[string]interface{}{
+			"defaults":  map[string]interface{}{"unit": unit},
+			"overrides": []interface{}{},
+		},
+		"targets": targets,
+	}
+}
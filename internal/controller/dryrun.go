@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// planPathDepth is how deep the changed fields of an updated child are reported, e.g.
+// spec.template.spec rather than every container field below it
+const planPathDepth = 3
+
+// reconcileDryRun records the changes Reconcile would make to the children in Status.Plan,
+// without creating, updating or deleting any of them. previousPlan is the plan recorded before,
+// a change of it is recorded as an Event
+func (r *InferenceSchedulerReconciler) reconcileDryRun(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler, previousPlan []string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	objects := BuildChildObjects(infScheduler)
+	plan := []string{}
+	for _, obj := range objects {
+		change, err := r.planChild(ctx, obj, infScheduler)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if change != "" {
+			plan = append(plan, change)
+		}
+	}
+
+	orphans, err := r.findOrphans(ctx, infScheduler)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, obj := range orphans {
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		plan = append(plan, fmt.Sprintf("delete %s %s", gvk.Kind, obj.GetName()))
+	}
+
+	message := fmt.Sprintf("Dry run: %d of %d children would be created or updated, %d deleted",
+		len(plan)-len(orphans), len(objects), len(orphans))
+	logger.Info("Dry run, not applying children", "plan", plan)
+	if !reflect.DeepEqual(plan, previousPlan) {
+		r.recordEvent(infScheduler, corev1.EventTypeNormal, "DryRun", message)
+	}
+	infScheduler.Status.Plan = plan
+	infScheduler.Status.Phase = "DryRun"
+	r.updateCondition(infScheduler, "DryRun", metav1.ConditionTrue, "PlanRecorded", message)
+	if err := r.Status().Update(ctx, infScheduler); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The cluster may drift from the plan without any child event, refresh it periodically
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// planChild describes the change createOrUpdate would make to obj, or returns "" if it would
+// leave the child as it is. It follows the same steps as createOrUpdate up to the write
+func (r *InferenceSchedulerReconciler) planChild(ctx context.Context, obj client.Object, owner client.Object) (string, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return "", err
+	}
+	var existing client.Object
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		existing = u
+	} else {
+		existing = obj.DeepCopyObject().(client.Object)
+	}
+	setManagedMetadata(obj)
+	setAppliedHash(obj)
+	setLastApplied(obj)
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Sprintf("create %s %s", gvk.Kind, obj.GetName()), nil
+		}
+		return "", err
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	obj.SetOwnerReferences(existing.GetOwnerReferences())
+	if err := r.setOwnerReference(owner, obj); err != nil {
+		return "", err
+	}
+	skip, err := keepIgnoredDrift(obj, existing)
+	if err != nil {
+		return "", err
+	}
+	if skip {
+		return "", nil
+	}
+	keepAutoscaledReplicas(obj, existing)
+	keepBoundClaim(obj, existing)
+	if upToDate(obj, existing) {
+		return "", nil
+	}
+
+	patch, err := childPatch(obj, existing)
+	if err != nil {
+		return "", err
+	}
+	var changes map[string]interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return "", err
+	}
+	// The bookkeeping annotations change with every update and say nothing about it
+	if metadata, ok := changes["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, appliedHashAnnotation)
+			delete(annotations, lastAppliedAnnotation)
+		}
+	}
+	paths := changedPaths(changes, "", planPathDepth)
+	if len(paths) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("update %s %s: %s", gvk.Kind, obj.GetName(), strings.Join(paths, ", ")), nil
+}
+
+// changedPaths returns the sorted field paths set or removed by a JSON merge patch, down to
+// depth levels. Objects that end up empty are left out
+func changedPaths(patch map[string]interface{}, prefix string, depth int) []string {
+	var paths []string
+	for key, value := range patch {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			if len(nested) == 0 {
+				continue
+			}
+			if depth > 1 {
+				paths = append(paths, changedPaths(nested, path, depth-1)...)
+				continue
+			}
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
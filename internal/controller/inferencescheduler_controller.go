@@ -18,14 +18,22 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,46 +41,215 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+	"github.com/aneeshkp/inference-scheduler-operator/internal/validation"
 )
 
 const (
-	finalizerName = "llm.llm-d.io/finalizer"
+	// DefaultFinalizerName is used unless the reconciler is configured with another finalizer
+	DefaultFinalizerName = "llm.llm-d.io/finalizer"
+
+	// DefaultPrerequisiteRecheckInterval is the first interval to check missing prerequisites again
+	DefaultPrerequisiteRecheckInterval = 60 * time.Second
+
+	// dryRunAnnotation set to "true" makes the reconciler record the changes it would make to the
+	// children in Status.Plan instead of applying them
+	dryRunAnnotation = "llm.llm-d.io/dry-run"
+
+	// skipGatewayClassCheckAnnotation makes validatePrerequisites only warn about a missing GatewayClass,
+	// for clusters where the GatewayClass is provisioned just in time
+	skipGatewayClassCheckAnnotation = "llm.llm-d.io/skip-gatewayclass-check"
+
+	// Prerequisite names reported in Status.MissingPrerequisites
+	prereqGatewayAPI      = "GatewayAPI"
+	prereqHTTPRoute       = "HTTPRoute"
+	prereqGIE             = "GatewayAPIInferenceExtension"
+	prereqGatewayClassCRD = "GatewayClassCRD"
+	prereqGatewayClass    = "GatewayClass"
+
+	// managedByLabel and operatorVersionAnnotation are set on every resource the operator creates
+	managedByLabel            = "app.kubernetes.io/managed-by"
+	managedByValue            = "inference-scheduler-operator"
+	operatorVersionAnnotation = "llm.llm-d.io/operator-version"
+
+	// appliedHashAnnotation records the hash of the desired state a child was last written with
+	appliedHashAnnotation = "llm.llm-d.io/applied-hash"
+
+	// lastAppliedAnnotation records the desired content a child was last written with, so fields
+	// the operator stops setting are removed while fields set by others are left alone
+	lastAppliedAnnotation = "llm.llm-d.io/last-applied"
+
+	// ignoreDriftAnnotation on a child opts it out of drift reversion: "true" leaves the whole object
+	// alone, a comma-separated list of field paths such as "spec.replicas,metadata.annotations"
+	// keeps those fields at their live values
+	ignoreDriftAnnotation = "llm.llm-d.io/ignore-drift"
+
+	// instanceLabel on the EPP and model server Services lets the ServiceMonitor select the Services
+	// of one InferenceScheduler. The Services and the InferencePool also select pods on it
+	instanceLabel = "app.kubernetes.io/instance"
+
+	// modelServerLabel on the pods and Service of an extra model server holds its name, so the
+	// workloads of an InferenceScheduler never select each other's pods
+	modelServerLabel = "llm.llm-d.io/model-server"
+
+	// configHashAnnotation records the hash of the EPP plugin config on the ConfigMap and the EPP
+	// pod template, so a config change rolls the pods that only read it at startup
+	configHashAnnotation = "llm.llm-d.io/config-hash"
+
+	// Pod annotations read by annotation based Prometheus scrape configs
+	prometheusScrapeAnnotation = "prometheus.io/scrape"
+	prometheusPortAnnotation   = "prometheus.io/port"
+	prometheusPathAnnotation   = "prometheus.io/path"
+
+	// replicasFromIndex indexes InferenceSchedulers by the ConfigMap named in ReplicasFrom
+	replicasFromIndex = "spec.modelServer.replicasFrom.name"
+
+	// routingImmediate creates the routing children without waiting for the model server
+	routingImmediate = "Immediate"
+
+	// backendNotReadyMessage is the GatewayReady message while no model server serves the routes
+	backendNotReadyMessage = "Gateway and HTTPRoute have no ready model server to route to"
+
+	// workloadStatefulSet selects a StatefulSet for the model server instead of a Deployment
+	workloadStatefulSet = "StatefulSet"
+
+	// logFormatJSON switches the model server to JSON logs
+	logFormatJSON = "json"
+	// modelServerLoggingPath is where the generated vLLM logging config is mounted
+	modelServerLoggingPath = "/etc/vllm/logging"
+	// modelServerHomePath is the writable HOME of vLLM when its root filesystem is read-only
+	modelServerHomePath = "/home/vllm"
+	// modelServerUser and modelServerGroup run vLLM as non-root unless a pod security context is set
+	modelServerUser  = 1000
+	modelServerGroup = 1000
 
 	// Default values
-	defaultModelServerImage = "vllm/vllm-openai:latest"
-	defaultEPPImage        = "ghcr.io/llm-d/llm-d-inference-scheduler:v0.3.2"
-	defaultModelServerPort = 8000
-	defaultEPPGRPCPort     = 9002
-	defaultGatewayPort     = 80
+	defaultModelServerImage    = "vllm/vllm-openai:latest"
+	defaultEPPImage            = "ghcr.io/llm-d/llm-d-inference-scheduler:v0.3.2"
+	defaultModelServerPort     = 8000
+	defaultModelCachePath      = "/root/.cache/huggingface"
+	defaultEPPGRPCPort         = 9002
+	defaultEPPHealthPort       = 9003
+	defaultEPPMetricsPort      = 9090
+	defaultEPPLogVerbosity     = 2
+	defaultEPPConfigAPIVersion = "inference.networking.x-k8s.io/v1alpha1"
+	defaultGatewayPort         = 80
+	defaultGatewayTLSPort      = 443
+	// defaultModelServerTerminationGracePeriod leaves streaming responses time to finish
+	defaultModelServerTerminationGracePeriod int64 = 60
 )
 
+// Version is the operator version recorded on the resources it creates. It is set at build time
+// with -ldflags "-X github.com/aneeshkp/inference-scheduler-operator/internal/controller.Version=..."
+var Version = "dev"
+
+// componentConditions are the conditions aggregated into Ready and Degraded
+var componentConditions = []string{"PrerequisitesValidated", "ModelServerReady", "EPPReady", "InferencePoolReady", "GatewayReady"}
+
+// typedChildKinds are the kinds with Go types the operator creates. SetupWithManager owns all of
+// them, so an edited or deleted child is repaired right away
+var typedChildKinds = []client.Object{
+	&appsv1.Deployment{},
+	&appsv1.StatefulSet{},
+	&autoscalingv2.HorizontalPodAutoscaler{},
+	&policyv1.PodDisruptionBudget{},
+	&corev1.Service{},
+	&corev1.ServiceAccount{},
+	&corev1.ConfigMap{},
+	&corev1.PersistentVolumeClaim{},
+	&rbacv1.Role{},
+	&rbacv1.RoleBinding{},
+}
+
+// unstructuredChildGVKs are the kinds created through unstructured objects, since their
+// Go types are not part of the operator's dependencies
+var unstructuredChildGVKs = []schema.GroupVersionKind{
+	{Group: "inference.networking.k8s.io", Version: "v1", Kind: "InferencePool"},
+	{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"},
+	{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"},
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"},
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"},
+}
+
 // InferenceSchedulerReconciler reconciles a InferenceScheduler object
 type InferenceSchedulerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// FinalizerName is the finalizer added to InferenceSchedulers. DefaultFinalizerName is used if empty.
+	// When it differs from the default, the default finalizer is migrated to it
+	FinalizerName string
+
+	// ModelCatalog is the ConfigMap that lists the models served by all InferenceSchedulers.
+	// No catalog is maintained if its name is empty
+	ModelCatalog types.NamespacedName
+
+	// APIReader reads Secrets and Pods directly from the API server, so they are not cached
+	// cluster-wide. The cached client is used if it is not set
+	APIReader client.Reader
+
+	// Recorder emits Events on InferenceSchedulers. SetupWithManager sets one if it is nil
+	Recorder record.EventRecorder
+
+	// PrerequisiteRecheckInterval is how long to wait before checking missing prerequisites again.
+	// The interval doubles for every recheck. DefaultPrerequisiteRecheckInterval is used if zero
+	PrerequisiteRecheckInterval time.Duration
+
+	// MaxPrerequisiteRechecks stops the rechecks of missing prerequisites after this many. The
+	// InferenceScheduler is then only checked again on a spec change or a CRD installation.
+	// Zero rechecks forever
+	MaxPrerequisiteRechecks int
+
+	// ResourceDefaults are the requests and limits of the containers that leave them unset.
+	// DefaultResources is used if nil
+	ResourceDefaults *ResourceDefaults
+
+	// watches adds watches for unstructured children whose CRDs were installed late
+	watches *childWatches
+
+	// backoff grows the requeue interval while an InferenceScheduler keeps waiting
+	backoff requeueBackoff
 }
 
 // +kubebuilder:rbac:groups=llm.llm-d.io,resources=inferenceschedulers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=llm.llm-d.io,resources=inferenceschedulers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=llm.llm-d.io,resources=inferenceschedulers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=inference.networking.k8s.io,resources=inferencepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 
-func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Starting reconciliation", "name", req.Name, "namespace", req.Namespace)
 
@@ -81,7 +258,9 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err := r.Get(ctx, req.NamespacedName, infScheduler); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("InferenceScheduler resource not found, ignoring since object must be deleted")
-			return ctrl.Result{}, nil
+			phases.forget(req.NamespacedName)
+			r.backoff.forget(req.NamespacedName)
+			return ctrl.Result{}, r.removeFromModelCatalog(ctx, req.NamespacedName)
 		}
 		logger.Error(err, "Failed to get InferenceScheduler")
 		return ctrl.Result{}, err
@@ -89,12 +268,77 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	// Handle deletion
 	if !infScheduler.ObjectMeta.DeletionTimestamp.IsZero() {
+		phases.forget(req.NamespacedName)
+		r.backoff.forget(req.NamespacedName)
+		if err := r.removeFromModelCatalog(ctx, req.NamespacedName); err != nil {
+			return ctrl.Result{}, err
+		}
 		return r.handleDeletion(ctx, infScheduler)
 	}
 
-	// Add finalizer if not present
-	if !controllerutil.ContainsFinalizer(infScheduler, finalizerName) {
-		controllerutil.AddFinalizer(infScheduler, finalizerName)
+	// Creating children in a terminating namespace fails, and the InferenceScheduler is about to be deleted anyway
+	terminating, err := r.isNamespaceTerminating(ctx, infScheduler.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if terminating {
+		logger.Info("Namespace is terminating, skipping reconciliation", "namespace", infScheduler.Namespace)
+		infScheduler.Status.Phase = "Terminating"
+		r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, "NamespaceTerminating",
+			fmt.Sprintf("Namespace %s is being deleted, no resources are created or updated", infScheduler.Namespace))
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+
+	// Export the phase reached by this reconcile on the operator's metrics endpoint
+	defer func() {
+		phases.record(req.NamespacedName, getDefaultString(infScheduler.Status.Phase, "Unknown"))
+	}()
+
+	// Keep the model catalog in sync with the phase reached by this reconcile
+	defer func() {
+		if err := r.updateModelCatalog(ctx, infScheduler); err != nil {
+			logger.Error(err, "Failed to update the model catalog")
+		}
+	}()
+
+	// Summarize the component conditions once this reconcile has updated them. Registered before
+	// the ownership conflict handling so it runs after it
+	defer func() {
+		if r.aggregateConditions(infScheduler) {
+			r.Status().Update(ctx, infScheduler)
+		}
+	}()
+
+	// A child controlled by another controller is reported rather than fought over. The conflict is
+	// only resolved by a person, so it is retried slowly instead of with backoff
+	defer func() {
+		if conflict, ok := err.(*controllerutil.AlreadyOwnedError); ok {
+			message := fmt.Sprintf("%s/%s is controlled by %s %s and is not adopted; delete it or rename the InferenceScheduler",
+				conflict.Object.GetNamespace(), conflict.Object.GetName(), conflict.Owner.Kind, conflict.Owner.Name)
+			logger.Info("Child is controlled by another controller", "conflict", message)
+			if r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, "OwnershipConflict", message) {
+				r.recordEvent(infScheduler, corev1.EventTypeWarning, "OwnershipConflict", message)
+			}
+			r.Status().Update(ctx, infScheduler)
+			result, err = ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+			return
+		}
+		if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded"); err == nil && cond != nil && cond.Reason == "OwnershipConflict" {
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "OwnershipResolved", "No child is controlled by another controller")
+			r.Status().Update(ctx, infScheduler)
+		}
+	}()
+
+	// Add finalizer if not present, replacing the default one if another name is configured
+	finalizer := r.finalizer()
+	legacy := finalizer != DefaultFinalizerName && controllerutil.ContainsFinalizer(infScheduler, DefaultFinalizerName)
+	if !controllerutil.ContainsFinalizer(infScheduler, finalizer) || legacy {
+		controllerutil.AddFinalizer(infScheduler, finalizer)
+		if legacy {
+			logger.Info("Migrating finalizer", "from", DefaultFinalizerName, "to", finalizer)
+			controllerutil.RemoveFinalizer(infScheduler, DefaultFinalizerName)
+		}
 		if err := r.Update(ctx, infScheduler); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -111,59 +355,455 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 	// Phase 1: Validate Prerequisites
 	logger.Info("Validating prerequisites (Gateway API, GIE, GatewayClass)")
 	if err := r.validatePrerequisites(ctx, infScheduler); err != nil {
+		if _, missing := err.(*missingPrerequisitesError); !missing {
+			// The API server could not answer, retry with backoff without judging the prerequisites
+			logger.Error(err, "Failed to check prerequisites")
+			return ctrl.Result{}, err
+		}
 		logger.Error(err, "Prerequisites validation failed")
+		if infScheduler.Status.PrerequisitesValidated {
+			// The scheduler was already deployed, so the prerequisites were removed afterwards.
+			// Leave the running resources untouched instead of failing to apply them
+			logger.Info("Prerequisites were removed after deployment, leaving existing resources untouched")
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, "PrerequisitesRemoved",
+				fmt.Sprintf("Prerequisites were removed after deployment, existing resources are left running but not updated: %v", err))
+		}
+		// Installed CRDs trigger a reconcile, but a missing GatewayClass does not, so check again,
+		// less often the longer they stay missing, until the rechecks are used up
+		message := err.Error()
+		exhausted := r.MaxPrerequisiteRechecks > 0 &&
+			r.backoff.requeues(req.NamespacedName, waitPrerequisites) >= r.MaxPrerequisiteRechecks
+		if exhausted {
+			message = fmt.Sprintf("%s. Stopped rechecking after %d attempts, waiting for a spec change or a CRD installation",
+				message, r.MaxPrerequisiteRechecks)
+		}
 		infScheduler.Status.PrerequisitesValidated = false
 		infScheduler.Status.PrerequisiteMessage = err.Error()
 		infScheduler.Status.Phase = "PrerequisitesMissing"
-		r.updateCondition(infScheduler, "PrerequisitesValidated", metav1.ConditionFalse, "ValidationFailed", err.Error())
+		if r.updateCondition(infScheduler, "PrerequisitesValidated", metav1.ConditionFalse, "ValidationFailed", message) {
+			r.recordEvent(infScheduler, corev1.EventTypeWarning, "PrerequisitesMissing", err.Error())
+		}
 		r.Status().Update(ctx, infScheduler)
-		// Requeue after 60 seconds to check again
-		return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		if exhausted {
+			logger.Info("Stopped rechecking prerequisites", "rechecks", r.MaxPrerequisiteRechecks)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: r.backoff.next(req.NamespacedName, waitPrerequisites, r.prerequisiteRecheckInterval())}, nil
 	}
+	r.backoff.reset(req.NamespacedName, waitPrerequisites)
 
 	// Prerequisites validated successfully
 	if !infScheduler.Status.PrerequisitesValidated {
 		infScheduler.Status.PrerequisitesValidated = true
 		infScheduler.Status.PrerequisiteMessage = "All prerequisites validated successfully"
 		r.updateCondition(infScheduler, "PrerequisitesValidated", metav1.ConditionTrue, "Validated", "Gateway API, GIE, and GatewayClass are present")
+		r.recordEvent(infScheduler, corev1.EventTypeNormal, "PrerequisitesValidated", "Gateway API, GIE, and GatewayClass are present")
 		logger.Info("Prerequisites validated successfully")
 	}
 
+	// Clear a previously reported prerequisite removal now that they are back
+	if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded"); cond != nil && cond.Reason == "PrerequisitesRemoved" {
+		r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "PrerequisitesRestored", "Prerequisites are present again")
+	}
+
+	// The prerequisite CRDs exist now, make sure their instances are watched
+	r.watches.ensure(ctx)
+
+	// A spec that fails validation has nothing planned, so a plan from an earlier spec is dropped
+	previousPlan := infScheduler.Status.Plan
+	infScheduler.Status.Plan = nil
+
+	// A model name vLLM cannot resolve only shows up as a crash looping pod, so nothing is deployed
+	if errs := validateModelNames(infScheduler.Spec); len(errs) > 0 {
+		message := strings.Join(errs, "; ")
+		logger.Info("Invalid model name", "errors", errs)
+		if r.updateCondition(infScheduler, "ModelNameValid", metav1.ConditionFalse, "InvalidModelName", message) {
+			r.recordEvent(infScheduler, corev1.EventTypeWarning, "InvalidModelName", message)
+		}
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	r.updateCondition(infScheduler, "ModelNameValid", metav1.ConditionTrue, "Valid", "Model names are valid")
+
+	if warnings := validateSidecars(infScheduler.Spec.ModelServer.Sidecars); len(warnings) > 0 {
+		logger.Info("Model server sidecar configuration has warnings", "warnings", warnings)
+		r.updateCondition(infScheduler, "SidecarConfigValid", metav1.ConditionFalse, "SidecarRequestsGPU", strings.Join(warnings, "; "))
+	} else {
+		r.updateCondition(infScheduler, "SidecarConfigValid", metav1.ConditionTrue, "Valid", "Model server sidecar configuration is valid")
+	}
+
+	// Duplicate variables would silently override each other, possibly replacing HF_TOKEN
+	if errs := validateTokenSecrets(infScheduler.Spec.ModelServer.AdditionalTokenSecrets); len(errs) > 0 {
+		logger.Info("Invalid additional token secrets", "errors", errs)
+		r.updateCondition(infScheduler, "TokenSecretsValid", metav1.ConditionFalse, "DuplicateEnvName", strings.Join(errs, "; "))
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	r.updateCondition(infScheduler, "TokenSecretsValid", metav1.ConditionTrue, "Valid", "Token secret environment variables are unique")
+
+	// Capacity managed outside the InferenceScheduler overrides the static replica count.
+	// The ConfigMap is watched, so a missing or invalid value is retried once it is fixed
+	if ref := infScheduler.Spec.ModelServer.ReplicasFrom; ref != nil {
+		replicas, err := r.replicasFromConfigMap(ctx, infScheduler.Namespace, ref)
+		if err != nil {
+			logger.Info("Failed to read model server replicas", "configMap", ref.Name, "key", ref.Key, "error", err.Error())
+			r.updateCondition(infScheduler, "ReplicasSourceValid", metav1.ConditionFalse, "InvalidReplicasSource", err.Error())
+			r.Status().Update(ctx, infScheduler)
+			return ctrl.Result{}, nil
+		}
+		if replicas != nil {
+			infScheduler.Spec.ModelServer.Replicas = *replicas
+			r.updateCondition(infScheduler, "ReplicasSourceValid", metav1.ConditionTrue, "Valid",
+				fmt.Sprintf("Model server replicas read from ConfigMap %s", ref.Name))
+		} else {
+			r.updateCondition(infScheduler, "ReplicasSourceValid", metav1.ConditionTrue, "FallbackToReplicas",
+				fmt.Sprintf("Optional ConfigMap %s has no key %s, using replicas", ref.Name, ref.Key))
+		}
+	}
+
+	if err := validateModelCache(infScheduler.Spec.ModelServer.ModelCache, maxModelServerReplicas(infScheduler)); err != nil {
+		logger.Info("Invalid model cache", "error", err.Error())
+		r.updateCondition(infScheduler, "ModelCacheValid", metav1.ConditionFalse, "InvalidVolumeSource", err.Error())
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	// The condition only reports on a configured model cache, an earlier failure goes with it
+	if infScheduler.Spec.ModelServer.ModelCache != nil {
+		r.updateCondition(infScheduler, "ModelCacheValid", metav1.ConditionTrue, "Valid", "Model cache volume is valid")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "ModelCacheValid")
+	}
+
+	if errs := validateExtraModelServers(infScheduler.Spec); len(errs) > 0 {
+		logger.Info("Invalid extra model servers", "errors", errs)
+		r.updateCondition(infScheduler, "ExtraModelServersValid", metav1.ConditionFalse, "InvalidModelServer", strings.Join(errs, "; "))
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	if len(infScheduler.Spec.ExtraModelServers) > 0 {
+		r.updateCondition(infScheduler, "ExtraModelServersValid", metav1.ConditionTrue, "Valid", "Extra model servers are valid")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "ExtraModelServersValid")
+	}
+
+	if errs := validateEPPPorts(infScheduler.Spec.EndpointPicker); len(errs) > 0 {
+		logger.Info("Invalid EPP ports", "errors", errs)
+		r.updateCondition(infScheduler, "EPPPortsValid", metav1.ConditionFalse, "PortConflict", strings.Join(errs, "; "))
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	if epp := infScheduler.Spec.EndpointPicker; epp.HealthPort != nil || epp.MetricsPort != nil {
+		r.updateCondition(infScheduler, "EPPPortsValid", metav1.ConditionTrue, "Valid", "EPP ports are distinct")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "EPPPortsValid")
+	}
+
+	if infScheduler.Spec.EndpointPicker.NodePort != nil {
+		if errs := validateEPPNodePort(infScheduler.Spec.EndpointPicker); len(errs) > 0 {
+			logger.Info("Invalid EPP node ports", "errors", errs)
+			r.updateCondition(infScheduler, "EPPNodePortValid", metav1.ConditionFalse, "InvalidNodePort", strings.Join(errs, "; "))
+			r.Status().Update(ctx, infScheduler)
+			return ctrl.Result{}, nil
+		}
+		r.updateCondition(infScheduler, "EPPNodePortValid", metav1.ConditionTrue, "Valid", "EPP node ports are valid")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "EPPNodePortValid")
+	}
+
+	if err := validateModelServerShutdown(infScheduler.Spec.ModelServer); err != nil {
+		logger.Info("Invalid model server shutdown settings", "error", err.Error())
+		r.updateCondition(infScheduler, "ModelServerShutdownValid", metav1.ConditionFalse, "PreStopTooLong", err.Error())
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	if infScheduler.Spec.ModelServer.PreStopSleepSeconds != nil {
+		r.updateCondition(infScheduler, "ModelServerShutdownValid", metav1.ConditionTrue, "Valid", "Model server shutdown settings are valid")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "ModelServerShutdownValid")
+	}
+
+	if spec := infScheduler.Spec.ModelServer.Service; spec != nil && spec.NodePort != nil {
+		if err := validateModelServerService(spec); err != nil {
+			logger.Info("Invalid model server Service", "error", err.Error())
+			r.updateCondition(infScheduler, "ModelServerServiceValid", metav1.ConditionFalse, "InvalidNodePort", err.Error())
+			r.Status().Update(ctx, infScheduler)
+			return ctrl.Result{}, nil
+		}
+		r.updateCondition(infScheduler, "ModelServerServiceValid", metav1.ConditionTrue, "Valid", "Model server node port is valid")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "ModelServerServiceValid")
+	}
+
+	if errs := validatePodDisruptionBudgets(infScheduler.Spec); len(errs) > 0 {
+		logger.Info("Invalid PodDisruptionBudgets", "errors", errs)
+		r.updateCondition(infScheduler, "PodDisruptionBudgetValid", metav1.ConditionFalse, "InvalidBudget", strings.Join(errs, "; "))
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	if infScheduler.Spec.ModelServer.PodDisruptionBudget != nil || infScheduler.Spec.EndpointPicker.PodDisruptionBudget != nil {
+		r.updateCondition(infScheduler, "PodDisruptionBudgetValid", metav1.ConditionTrue, "Valid", "PodDisruptionBudgets are valid")
+	} else {
+		meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "PodDisruptionBudgetValid")
+	}
+
+	if err := validation.PluginConfigSource(infScheduler.Spec.EndpointPicker); err != nil {
+		logger.Info("Invalid EPP plugin configuration", "error", err.Error())
+		r.updateCondition(infScheduler, "PluginConfigValid", metav1.ConditionFalse, "ConflictingConfigSources", err.Error())
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+
+	if errs := validateExistingGateway(infScheduler.Spec.Gateway); len(errs) > 0 {
+		logger.Info("Invalid gateway configuration", "errors", errs)
+		r.updateCondition(infScheduler, "GatewayConfigValid", metav1.ConditionFalse, "ConflictingGatewaySettings", strings.Join(errs, "; "))
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{}, nil
+	}
+	if infScheduler.Spec.Gateway.ExistingGatewayRef != nil {
+		r.updateCondition(infScheduler, "GatewayConfigValid", metav1.ConditionTrue, "Valid", "Gateway configuration is valid")
+	} else {
+		// Exposure depends on the gateway implementation, so mismatches are reported but allowed
+		if warnings := validation.GatewayExposure(infScheduler.Spec.Gateway); len(warnings) > 0 {
+			logger.Info("Gateway configuration has warnings", "warnings", warnings)
+			r.updateCondition(infScheduler, "GatewayConfigValid", metav1.ConditionFalse, "IncompatibleExposure", strings.Join(warnings, "; "))
+		} else {
+			r.updateCondition(infScheduler, "GatewayConfigValid", metav1.ConditionTrue, "Valid", "Gateway configuration is valid")
+		}
+
+		conflicts, err := r.gatewayListenerConflicts(ctx, r.buildGateway(infScheduler), infScheduler)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(conflicts) > 0 {
+			message := "Gateway listeners conflict: " + strings.Join(conflicts, "; ")
+			logger.Info("Gateway listeners conflict", "conflicts", conflicts)
+			if r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionFalse, "ListenerConflict", message) {
+				r.recordEvent(infScheduler, corev1.EventTypeWarning, "GatewayListenerConflict", message)
+			}
+			infScheduler.Status.GatewayReady = false
+			r.Status().Update(ctx, infScheduler)
+			// A Gateway that is not ours is not watched, so check again for the conflict to be resolved
+			return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		}
+	}
+
+	// A missing token secret or key only shows up as a rejected download on the pods
+	if infScheduler.Spec.ModelServer.HFTokenSecretName == "" {
+		// Gated models are still reported once HuggingFace rejects the download
+		if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "HFTokenValid"); cond == nil || cond.Reason != "DownloadUnauthorized" {
+			r.updateCondition(infScheduler, "HFTokenValid", metav1.ConditionTrue, "NoToken", "No HuggingFace token is configured")
+		}
+	} else if err := r.validateHFTokenSecret(ctx, infScheduler); err != nil {
+		logger.Info("HuggingFace token secret is invalid", "error", err.Error())
+		r.updateCondition(infScheduler, "HFTokenValid", metav1.ConditionFalse, "InvalidSecret", err.Error())
+	} else if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "HFTokenValid"); cond == nil || cond.Reason != "DownloadUnauthorized" {
+		// A rejected token is only cleared once the model server becomes ready
+		r.updateCondition(infScheduler, "HFTokenValid", metav1.ConditionTrue, "SecretValid",
+			fmt.Sprintf("Secret %s contains the token key", infScheduler.Spec.ModelServer.HFTokenSecretName))
+	}
+
+	// The plan reflects the validated spec and the replicas read from replicasFrom, like the apply
+	if infScheduler.Annotations[dryRunAnnotation] == "true" {
+		return r.reconcileDryRun(ctx, infScheduler, previousPlan)
+	}
+	meta.RemoveStatusCondition(&infScheduler.Status.Conditions, "DryRun")
+
+	// Switch the route first, the model server may already be scaled down for the maintenance.
+	// The spec is validated above, so the maintenance routes are only applied for a valid spec
+	if infScheduler.Spec.MaintenanceMode {
+		logger.Info("Maintenance mode enabled, routing is disabled")
+		if infScheduler.Spec.Gateway.ExistingGatewayRef == nil {
+			if err := r.createOrUpdateUnstructured(ctx, r.buildGateway(infScheduler), infScheduler); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.createOrUpdateUnstructured(ctx, r.buildHTTPRoute(infScheduler), infScheduler); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.updateCondition(infScheduler, "Maintenance", metav1.ConditionTrue, "MaintenanceModeEnabled",
+			"HTTPRoute returns 503 until maintenanceMode is disabled")
+	} else if meta.FindStatusCondition(infScheduler.Status.Conditions, "Maintenance") != nil {
+		r.updateCondition(infScheduler, "Maintenance", metav1.ConditionFalse, "MaintenanceModeDisabled", "Routing to the InferencePool is enabled")
+	}
+
 	infScheduler.Status.Phase = "Deploying"
+	if infScheduler.Spec.MaintenanceMode {
+		infScheduler.Status.Phase = "Maintenance"
+	}
 	r.Status().Update(ctx, infScheduler)
 
 	// Phase 4: Deploy Model Server
 	logger.Info("Deploying model server")
 
-	deployment := r.buildModelServerDeployment(infScheduler)
-	if err := r.createOrUpdate(ctx, deployment, infScheduler); err != nil {
-		logger.Error(err, "Failed to create/update model server deployment")
+	// rolloutHeld is set when a component update waits for the other component, see RolloutOrder
+	rolloutHeld := false
+
+	// The model cache claim and the logging config are mounted by the model server pods, so they
+	// have to exist first
+	if cache := infScheduler.Spec.ModelServer.ModelCache; cache != nil && cache.Claim != nil {
+		if err := r.createOrUpdate(ctx, r.buildModelCachePVC(infScheduler), infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update model cache PersistentVolumeClaim")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if infScheduler.Spec.ModelServer.LogFormat == logFormatJSON {
+		loggingConfigMap := r.buildModelServerLoggingConfigMap(infScheduler)
+		if err := r.createOrUpdate(ctx, loggingConfigMap, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update model server logging ConfigMap")
+			return ctrl.Result{}, err
+		}
+	}
+
+	modelServer := r.buildModelServerWorkload(infScheduler)
+	holdModelServer := false
+	if infScheduler.Spec.RolloutOrder == "EndpointPickerFirst" {
+		hold, err := r.holdRollout(ctx, modelServer, r.buildEPPDeployment(infScheduler))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		holdModelServer = hold
+	}
+	if holdModelServer {
+		logger.Info("Holding model server update until the EPP rollout completes")
+		rolloutHeld = true
+	} else if err := r.createOrUpdate(ctx, modelServer, infScheduler); err != nil {
+		logger.Error(err, "Failed to create/update model server workload")
 		r.updateCondition(infScheduler, "ModelServerReady", metav1.ConditionFalse, "DeploymentFailed", err.Error())
+		r.recordEvent(infScheduler, corev1.EventTypeWarning, "ModelServerFailed", err.Error())
 		r.Status().Update(ctx, infScheduler)
 		return ctrl.Result{}, err
 	}
 
+	if infScheduler.Spec.ModelServer.Autoscaling != nil {
+		hpa := r.buildModelServerHPA(infScheduler)
+		if err := r.createOrUpdate(ctx, hpa, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update model server HorizontalPodAutoscaler")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.reconcilePodDisruptionBudget(ctx, infScheduler, fmt.Sprintf("%s-vllm", infScheduler.Name), r.buildModelServerPDB(infScheduler)); err != nil {
+		logger.Error(err, "Failed to reconcile model server PodDisruptionBudget")
+		return ctrl.Result{}, err
+	}
+
 	service := r.buildModelServerService(infScheduler)
 	if err := r.createOrUpdate(ctx, service, infScheduler); err != nil {
 		logger.Error(err, "Failed to create/update model server service")
 		return ctrl.Result{}, err
 	}
 
-	// Check deployment readiness
-	ready, err := r.isDeploymentReady(ctx, deployment.Namespace, deployment.Name)
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		headlessService := r.buildModelServerHeadlessService(infScheduler)
+		if err := r.createOrUpdate(ctx, headlessService, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update model server headless service")
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, extra := range infScheduler.Spec.ExtraModelServers {
+		for _, obj := range r.buildExtraModelServerObjects(infScheduler, extra) {
+			if err := r.createOrUpdate(ctx, obj, infScheduler); err != nil {
+				logger.Error(err, "Failed to create/update extra model server", "modelServer", extra.Name)
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// Remove what the previously selected workload kind left behind
+	if err := r.deleteStaleModelServerWorkload(ctx, infScheduler); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Check workload readiness
+	var ready bool
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		ready, err = r.isStatefulSetReady(ctx, modelServer.GetNamespace(), modelServer.GetName())
+	} else {
+		ready, err = r.isDeploymentReady(ctx, modelServer.GetNamespace(), modelServer.GetName())
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	// Every model server joins the pool, so all of them have to be ready
+	for _, extra := range infScheduler.Spec.ExtraModelServers {
+		if !ready {
+			break
+		}
+		name := extraModelServerName(infScheduler, extra)
+		if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+			ready, err = r.isStatefulSetReady(ctx, infScheduler.Namespace, name)
+		} else {
+			ready, err = r.isDeploymentReady(ctx, infScheduler.Namespace, name)
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	deadlineExceeded := false
 	if !ready {
 		logger.Info("Waiting for model server deployment to be ready")
 		r.updateCondition(infScheduler, "ModelServerReady", metav1.ConditionFalse, "NotReady", "Model server pods are not ready yet")
 		infScheduler.Status.ModelServerReplicas = 0
-		r.Status().Update(ctx, infScheduler)
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
-	}
 
-	r.updateCondition(infScheduler, "ModelServerReady", metav1.ConditionTrue, "Ready", "All model server pods are running")
-	infScheduler.Status.ModelServerReplicas = infScheduler.Spec.ModelServer.Replicas
+		// Best effort, an error here must not hold up the readiness handling
+		if unauthorized, err := r.modelDownloadUnauthorized(ctx, infScheduler); err != nil {
+			logger.Error(err, "Failed to inspect model server pods")
+		} else if unauthorized {
+			message := fmt.Sprintf("HuggingFace rejected the model download; check that the token in secret %s is valid "+
+				"and has been granted access to %s", infScheduler.Spec.ModelServer.HFTokenSecretName, infScheduler.Spec.ModelServer.ModelName)
+			if infScheduler.Spec.ModelServer.HFTokenSecretName == "" {
+				message = fmt.Sprintf("HuggingFace rejected the model download; %s may be gated and need a token set through hfTokenSecretName",
+					infScheduler.Spec.ModelServer.ModelName)
+			}
+			r.updateCondition(infScheduler, "HFTokenValid", metav1.ConditionFalse, "DownloadUnauthorized", message)
+		}
+		r.reportCrashLoop(ctx, infScheduler, "ModelServerCrashLooping", r.buildModelServerDeployment(infScheduler).Spec.Selector.MatchLabels)
+
+		// Routes created earlier have no backend to send requests to
+		if meta.IsStatusConditionTrue(infScheduler.Status.Conditions, "GatewayReady") {
+			r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionFalse, "BackendNotReady", backendNotReadyMessage)
+			infScheduler.Status.GatewayReady = false
+		}
+
+		deadlineExceeded = r.readinessDeadlineExceeded(infScheduler)
+		if deadlineExceeded {
+			deadline := infScheduler.Spec.ModelServer.ReadinessDeadline.Duration
+			logger.Info("Model server readiness deadline exceeded", "deadline", deadline)
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, "ReadinessDeadlineExceeded",
+				fmt.Sprintf("Model server did not become ready within %s; check the model server pods for errors", deadline))
+		}
+
+		// With Immediate routing the EPP and routes are still applied, see the end of the reconcile
+		if infScheduler.Spec.RoutingOrder != routingImmediate {
+			r.Status().Update(ctx, infScheduler)
+			return ctrl.Result{RequeueAfter: r.modelServerWait(req.NamespacedName, deadlineExceeded)}, nil
+		}
+	} else {
+		r.backoff.reset(req.NamespacedName, waitModelServerReady)
+
+		// Clear a previously reported deadline failure or crash loop now that the model server is ready
+		if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded"); cond != nil &&
+			(cond.Reason == "ReadinessDeadlineExceeded" || cond.Reason == "ModelServerCrashLooping") {
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "ModelServerReady", "Model server became ready")
+		}
+
+		if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "HFTokenValid"); cond != nil && cond.Reason == "DownloadUnauthorized" {
+			r.updateCondition(infScheduler, "HFTokenValid", metav1.ConditionTrue, "ModelDownloaded", "Model server downloaded the model")
+		}
+
+		if r.updateCondition(infScheduler, "ModelServerReady", metav1.ConditionTrue, "Ready", "All model server pods are running") {
+			r.recordEvent(infScheduler, corev1.EventTypeNormal, "ModelServerReady", "All model server pods are running")
+		}
+		// With autoscaling the replica count is the HorizontalPodAutoscaler's, not the spec's
+		readyReplicas, err := r.modelServerReadyReplicas(ctx, infScheduler)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		infScheduler.Status.ModelServerReplicas = readyReplicas
+	}
+	modelServerReady := ready
 
 	// Phase 5: Deploy EPP
 	logger.Info("Deploying Endpoint Picker (EPP)")
@@ -184,19 +824,58 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
-	configMap := r.buildEPPConfigMap(infScheduler)
-	if err := r.createOrUpdate(ctx, configMap, infScheduler); err != nil {
-		return ctrl.Result{}, err
+	// Conflicting scorer settings are allowed but reported, since the EPP accepts them
+	if ref := infScheduler.Spec.EndpointPicker.PluginConfigMapRef; ref != nil {
+		r.updateCondition(infScheduler, "PluginConfigValid", metav1.ConditionTrue, "ConfigMapOverride",
+			fmt.Sprintf("EPP plugin configuration is read from key %s of ConfigMap %s", ref.Key, ref.Name))
+	} else if warnings := validatePluginConfig(infScheduler.Spec.EndpointPicker.Plugins); len(warnings) > 0 {
+		logger.Info("EPP plugin configuration has warnings", "warnings", warnings)
+		r.updateCondition(infScheduler, "PluginConfigValid", metav1.ConditionFalse, "ConflictingScorers", strings.Join(warnings, "; "))
+	} else {
+		r.updateCondition(infScheduler, "PluginConfigValid", metav1.ConditionTrue, "Valid", "EPP plugin configuration is valid")
+	}
+
+	// The generated ConfigMap is removed when the config is overridden, so it cannot be mistaken for the one in use
+	if configMap := r.buildEPPConfigMap(infScheduler); configMap != nil {
+		if err := r.createOrUpdate(ctx, configMap, infScheduler); err != nil {
+			return ctrl.Result{}, err
+		}
+		infScheduler.Status.ActivePlugins = activePlugins(configMap)
+		infScheduler.Status.PluginConfigHash = configMap.Annotations[configHashAnnotation]
+	} else {
+		key := types.NamespacedName{Name: fmt.Sprintf("%s-epp-config", infScheduler.Name), Namespace: infScheduler.Namespace}
+		if err := r.deleteOwned(ctx, infScheduler, key, &corev1.ConfigMap{}); err != nil {
+			return ctrl.Result{}, err
+		}
+		infScheduler.Status.ActivePlugins = nil
+		infScheduler.Status.PluginConfigHash = ""
 	}
 
 	eppDeployment := r.buildEPPDeployment(infScheduler)
-	if err := r.createOrUpdate(ctx, eppDeployment, infScheduler); err != nil {
+	holdEPP := false
+	if infScheduler.Spec.RolloutOrder == "ModelServerFirst" {
+		hold, err := r.holdRollout(ctx, eppDeployment, modelServer)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		holdEPP = hold
+	}
+	if holdEPP {
+		logger.Info("Holding EPP update until the model server rollout completes")
+		rolloutHeld = true
+	} else if err := r.createOrUpdate(ctx, eppDeployment, infScheduler); err != nil {
 		logger.Error(err, "Failed to create/update EPP deployment")
 		r.updateCondition(infScheduler, "EPPReady", metav1.ConditionFalse, "DeploymentFailed", err.Error())
+		r.recordEvent(infScheduler, corev1.EventTypeWarning, "EPPFailed", err.Error())
 		r.Status().Update(ctx, infScheduler)
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcilePodDisruptionBudget(ctx, infScheduler, fmt.Sprintf("%s-epp", infScheduler.Name), r.buildEPPPDB(infScheduler)); err != nil {
+		logger.Error(err, "Failed to reconcile EPP PodDisruptionBudget")
+		return ctrl.Result{}, err
+	}
+
 	eppService := r.buildEPPService(infScheduler)
 	if err := r.createOrUpdate(ctx, eppService, infScheduler); err != nil {
 		return ctrl.Result{}, err
@@ -211,11 +890,18 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 		logger.Info("Waiting for EPP deployment to be ready")
 		r.updateCondition(infScheduler, "EPPReady", metav1.ConditionFalse, "NotReady", "EPP pods are not ready yet")
 		infScheduler.Status.EPPReplicas = 0
+		r.reportCrashLoop(ctx, infScheduler, "EPPCrashLooping", eppDeployment.Spec.Selector.MatchLabels)
 		r.Status().Update(ctx, infScheduler)
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: r.backoff.next(req.NamespacedName, waitEPPReady, 30*time.Second)}, nil
+	}
+	r.backoff.reset(req.NamespacedName, waitEPPReady)
+	if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded"); cond != nil && cond.Reason == "EPPCrashLooping" {
+		r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "EPPReady", "EPP became ready")
 	}
 
-	r.updateCondition(infScheduler, "EPPReady", metav1.ConditionTrue, "Ready", "EPP is running")
+	if r.updateCondition(infScheduler, "EPPReady", metav1.ConditionTrue, "Ready", "EPP is running") {
+		r.recordEvent(infScheduler, corev1.EventTypeNormal, "EPPReady", "EPP is running")
+	}
 	infScheduler.Status.EPPReplicas = infScheduler.Spec.EndpointPicker.Replicas
 
 	// Phase 6: Create InferencePool
@@ -225,22 +911,41 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err := r.createOrUpdateUnstructured(ctx, inferencePool, infScheduler); err != nil {
 		logger.Error(err, "Failed to create/update InferencePool")
 		r.updateCondition(infScheduler, "InferencePoolReady", metav1.ConditionFalse, "CreationFailed", err.Error())
+		r.recordEvent(infScheduler, corev1.EventTypeWarning, "InferencePoolFailed", err.Error())
 		r.Status().Update(ctx, infScheduler)
 		return ctrl.Result{}, err
 	}
 
-	r.updateCondition(infScheduler, "InferencePoolReady", metav1.ConditionTrue, "Ready", "InferencePool created successfully")
-	infScheduler.Status.InferencePoolReady = true
-
 	// Phase 7: Create Gateway and HTTPRoute
 	logger.Info("Creating Gateway and HTTPRoute")
 
-	gateway := r.buildGateway(infScheduler)
-	if err := r.createOrUpdateUnstructured(ctx, gateway, infScheduler); err != nil {
-		logger.Error(err, "Failed to create/update Gateway")
-		r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionFalse, "CreationFailed", err.Error())
-		r.Status().Update(ctx, infScheduler)
-		return ctrl.Result{}, err
+	if infScheduler.Spec.Gateway.ExistingGatewayRef != nil {
+		// The Gateway is managed elsewhere, so it is neither created nor owned, only required to exist
+		found, err := r.existingGatewayFound(ctx, infScheduler)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !found {
+			key := gatewayKey(infScheduler)
+			message := fmt.Sprintf("Gateway %s/%s referenced by existingGatewayRef was not found", key.Namespace, key.Name)
+			logger.Info("Existing Gateway not found", "gateway", key)
+			if r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionFalse, "GatewayNotFound", message) {
+				r.recordEvent(infScheduler, corev1.EventTypeWarning, "GatewayNotFound", message)
+			}
+			infScheduler.Status.GatewayReady = false
+			r.Status().Update(ctx, infScheduler)
+			// A Gateway that is not ours is not watched, so check again for it to be created
+			return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
+		}
+	} else {
+		gateway := r.buildGateway(infScheduler)
+		if err := r.createOrUpdateUnstructured(ctx, gateway, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update Gateway")
+			r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionFalse, "CreationFailed", err.Error())
+			r.recordEvent(infScheduler, corev1.EventTypeWarning, "GatewayFailed", err.Error())
+			r.Status().Update(ctx, infScheduler)
+			return ctrl.Result{}, err
+		}
 	}
 
 	httpRoute := r.buildHTTPRoute(infScheduler)
@@ -249,101 +954,232 @@ func (r *InferenceSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
-	r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionTrue, "Ready", "Gateway and HTTPRoute created successfully")
-	infScheduler.Status.GatewayReady = true
+	if infScheduler.Spec.Gateway.HealthCheckRoute {
+		healthRoute := r.buildHealthCheckHTTPRoute(infScheduler)
+		if err := r.createOrUpdateUnstructured(ctx, healthRoute, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update health check HTTPRoute")
+			return ctrl.Result{}, err
+		}
+	}
 
-	// Final status update
-	infScheduler.Status.Phase = "Ready"
-	if err := r.Status().Update(ctx, infScheduler); err != nil {
-		return ctrl.Result{}, err
+	if tls := infScheduler.Spec.Gateway.TLS; tls != nil && tls.HTTPRedirect {
+		redirectRoute := r.buildHTTPSRedirectHTTPRoute(infScheduler)
+		if err := r.createOrUpdateUnstructured(ctx, redirectRoute, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update HTTPS redirect HTTPRoute")
+			return ctrl.Result{}, err
+		}
 	}
 
-	logger.Info("Reconciliation complete", "name", infScheduler.Name, "phase", infScheduler.Status.Phase)
+	if monitoring := infScheduler.Spec.Monitoring; monitoring != nil && monitoring.PrometheusRule {
+		prometheusRule := r.buildPrometheusRule(infScheduler)
+		if err := r.createOrUpdateUnstructured(ctx, prometheusRule, infScheduler); err != nil {
+			// Alerting is optional, so clusters without the Prometheus Operator are not an error
+			if !isKindMissing(err) {
+				logger.Error(err, "Failed to create/update PrometheusRule")
+				return ctrl.Result{}, err
+			}
+			logger.Info("Skipping PrometheusRule, the Prometheus Operator CRDs are not installed")
+		}
+	}
 
-	// Requeue after 5 minutes to check health
-	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
-}
+	// Without an endpoint to scrape the ServiceMonitor would select nothing
+	if serviceMonitor := r.buildServiceMonitor(infScheduler); serviceMonitor != nil {
+		if err := r.createOrUpdateUnstructured(ctx, serviceMonitor, infScheduler); err != nil {
+			if !isKindMissing(err) {
+				logger.Error(err, "Failed to create/update ServiceMonitor")
+				return ctrl.Result{}, err
+			}
+			logger.Info("Skipping ServiceMonitor, the Prometheus Operator CRDs are not installed")
+		}
+	}
 
-// handleDeletion handles the deletion of InferenceScheduler resources
-func (r *InferenceSchedulerReconciler) handleDeletion(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+	if dashboard := r.buildDashboardConfigMap(infScheduler); dashboard != nil {
+		if err := r.createOrUpdate(ctx, dashboard, infScheduler); err != nil {
+			logger.Error(err, "Failed to create/update Grafana dashboard ConfigMap")
+			return ctrl.Result{}, err
+		}
+	}
 
-	if !controllerutil.ContainsFinalizer(infScheduler, finalizerName) {
-		return ctrl.Result{}, nil
+	// Every desired child is applied now, so whatever else this InferenceScheduler controls is left
+	// over from an earlier spec
+	if err := r.deleteOrphans(ctx, infScheduler); err != nil {
+		logger.Error(err, "Failed to delete children no longer in the spec")
+		return ctrl.Result{}, err
 	}
 
-	logger.Info("Handling deletion", "name", infScheduler.Name)
+	if !modelServerReady {
+		r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionFalse, "BackendNotReady", backendNotReadyMessage)
+	} else if r.updateCondition(infScheduler, "GatewayReady", metav1.ConditionTrue, "Ready", "Gateway and HTTPRoute created successfully") {
+		r.recordEvent(infScheduler, corev1.EventTypeNormal, "GatewayReady", "Gateway and HTTPRoute created successfully")
+	}
+	infScheduler.Status.GatewayReady = modelServerReady
 
-	// Resources are automatically cleaned up due to owner references
-	// Additional cleanup can be added here if needed
+	// The gateway implementation assigns the address some time after the Gateway is created
+	address := r.gatewayAddress(ctx, infScheduler)
+	infScheduler.Status.GatewayAddress = address
+	infScheduler.Status.EndpointURL = gatewayEndpointURL(infScheduler, address)
 
-	// Remove finalizer
-	controllerutil.RemoveFinalizer(infScheduler, finalizerName)
-	if err := r.Update(ctx, infScheduler); err != nil {
-		return ctrl.Result{}, err
+	// The routes are in place ahead of the model server, which is still waited for
+	if !modelServerReady {
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{RequeueAfter: r.modelServerWait(req.NamespacedName, deadlineExceeded)}, nil
 	}
 
-	logger.Info("Finalizer removed", "name", infScheduler.Name)
-	return ctrl.Result{}, nil
-}
+	// No route references the pool during maintenance, so it cannot be accepted
+	if infScheduler.Spec.MaintenanceMode {
+		infScheduler.Status.ObservedGeneration = infScheduler.Generation
+		r.Status().Update(ctx, infScheduler)
+		logger.Info("Reconciliation complete", "name", infScheduler.Name, "phase", infScheduler.Status.Phase)
+		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
 
-// validatePrerequisites checks that all required prerequisites are installed
-// This follows the llm-d approach: operators declare dependencies, don't install them
-func (r *InferenceSchedulerReconciler) validatePrerequisites(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) error {
-	var missingPrereqs []string
+	// The gateway implementation only accepts the pool once the HTTPRoute references it, so the
+	// route cannot wait for acceptance. Instead the scheduler is not reported Ready until then
+	accepted, reason, err := r.isInferencePoolAccepted(ctx, inferencePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !accepted {
+		logger.Info("Waiting for the InferencePool to be accepted", "reason", reason)
+		r.updateCondition(infScheduler, "InferencePoolReady", metav1.ConditionFalse, "NotAccepted", reason)
+		infScheduler.Status.InferencePoolReady = false
+		r.Status().Update(ctx, infScheduler)
+		return ctrl.Result{RequeueAfter: r.backoff.next(req.NamespacedName, waitPoolAccepted, 15*time.Second)}, nil
+	}
+	r.backoff.reset(req.NamespacedName, waitPoolAccepted)
+
+	if r.updateCondition(infScheduler, "InferencePoolReady", metav1.ConditionTrue, "Ready", "InferencePool is accepted by the Gateway") {
+		r.recordEvent(infScheduler, corev1.EventTypeNormal, "InferencePoolReady", "InferencePool is accepted by the Gateway")
+	}
+	infScheduler.Status.InferencePoolReady = true
+
+	// Final status update
+	infScheduler.Status.Phase = "Ready"
+	infScheduler.Status.ObservedGeneration = infScheduler.Generation
+	if infScheduler.Status.TimeToReady == nil {
+		elapsed := time.Since(infScheduler.CreationTimestamp.Time).Round(time.Second)
+		infScheduler.Status.TimeToReady = &metav1.Duration{Duration: elapsed}
+		timeToReady.Observe(elapsed.Seconds())
+	}
+	if err := r.Status().Update(ctx, infScheduler); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Reconciliation complete", "name", infScheduler.Name, "phase", infScheduler.Status.Phase)
+
+	// Come back soon to apply the held update or to pick up the Gateway address
+	if rolloutHeld || address == "" {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Requeue after 5 minutes to check health
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// handleDeletion handles the deletion of InferenceScheduler resources
+func (r *InferenceSchedulerReconciler) handleDeletion(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Objects deleted before their finalizer was migrated still carry the default one
+	finalizer := r.finalizer()
+	if !controllerutil.ContainsFinalizer(infScheduler, finalizer) && !controllerutil.ContainsFinalizer(infScheduler, DefaultFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Handling deletion", "name", infScheduler.Name)
+
+	// Resources are automatically cleaned up due to owner references
+	// Additional cleanup can be added here if needed
+
+	// Remove finalizer
+	controllerutil.RemoveFinalizer(infScheduler, finalizer)
+	controllerutil.RemoveFinalizer(infScheduler, DefaultFinalizerName)
+	if err := r.Update(ctx, infScheduler); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Finalizer removed", "name", infScheduler.Name)
+	return ctrl.Result{}, nil
+}
+
+// missingPrerequisitesError reports prerequisites that are not installed in the cluster
+type missingPrerequisitesError struct {
+	descriptions []string
+}
+
+func (e *missingPrerequisitesError) Error() string {
+	return fmt.Sprintf("missing prerequisites: %s. See installation guide: https://github.com/aneeshkp/inference-scheduler-operator/blob/main/README.md#prerequisites", strings.Join(e.descriptions, "; "))
+}
+
+// validatePrerequisites checks that all required prerequisites are installed
+// This follows the llm-d approach: operators declare dependencies, don't install them
+// A *missingPrerequisitesError is returned if any are missing. Other errors come from the API
+// server and say nothing about the prerequisites, so they are returned as they are for a retry
+func (r *InferenceSchedulerReconciler) validatePrerequisites(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) error {
+	// missingPrereqs holds human readable descriptions, missingIDs the matching machine readable names
+	var missingPrereqs, missingIDs []string
 
 	// Check Gateway API CRDs exist
 	gatewayList := &unstructured.UnstructuredList{}
-	gatewayList.SetGroupVersionKind(schema.GroupVersionKind{
+	gatewayAPIFound, err := r.listPrerequisite(ctx, gatewayList, schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1",
 		Kind:    "Gateway",
-	})
-	if err := r.List(ctx, gatewayList, client.Limit(1)); err != nil {
-		if meta.IsNoMatchError(err) {
-			missingPrereqs = append(missingPrereqs, "Gateway API v1.3.0+ (install: kubectl apply -f https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.3.0/standard-install.yaml)")
-		}
+	}, client.Limit(1))
+	if err != nil {
+		return err
+	}
+	if !gatewayAPIFound {
+		missingPrereqs = append(missingPrereqs, "Gateway API v1.3.0+ (install: kubectl apply -f https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.3.0/standard-install.yaml)")
+		missingIDs = append(missingIDs, prereqGatewayAPI)
 	}
 
 	// Check HTTPRoute CRD exists
 	httpRouteList := &unstructured.UnstructuredList{}
-	httpRouteList.SetGroupVersionKind(schema.GroupVersionKind{
+	found, err := r.listPrerequisite(ctx, httpRouteList, schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1",
 		Kind:    "HTTPRoute",
-	})
-	if err := r.List(ctx, httpRouteList, client.Limit(1)); err != nil {
-		if meta.IsNoMatchError(err) && !contains(missingPrereqs, "Gateway API") {
-			missingPrereqs = append(missingPrereqs, "Gateway API HTTPRoute CRD")
-		}
+	}, client.Limit(1))
+	if err != nil {
+		return err
+	}
+	// A missing Gateway API is reported once, rather than once per kind
+	if !found && gatewayAPIFound {
+		missingPrereqs = append(missingPrereqs, "Gateway API HTTPRoute CRD")
+		missingIDs = append(missingIDs, prereqHTTPRoute)
 	}
 
 	// Check GIE CRDs exist
 	poolList := &unstructured.UnstructuredList{}
-	poolList.SetGroupVersionKind(schema.GroupVersionKind{
+	found, err = r.listPrerequisite(ctx, poolList, schema.GroupVersionKind{
 		Group:   "inference.networking.k8s.io",
 		Version: "v1",
 		Kind:    "InferencePool",
-	})
-	if err := r.List(ctx, poolList, client.Limit(1)); err != nil {
-		if meta.IsNoMatchError(err) {
-			missingPrereqs = append(missingPrereqs, "Gateway API Inference Extension v1.1.0+ (install: kubectl apply -f https://github.com/kubernetes-sigs/gateway-api-inference-extension/releases/download/v1.1.0/manifests.yaml)")
-		}
+	}, client.Limit(1))
+	if err != nil {
+		return err
+	}
+	if !found {
+		missingPrereqs = append(missingPrereqs, "Gateway API Inference Extension v1.1.0+ (install: kubectl apply -f https://github.com/kubernetes-sigs/gateway-api-inference-extension/releases/download/v1.1.0/manifests.yaml)")
+		missingIDs = append(missingIDs, prereqGIE)
 	}
 
 	// Check GatewayClass exists
 	gatewayClassList := &unstructured.UnstructuredList{}
-	gatewayClassList.SetGroupVersionKind(schema.GroupVersionKind{
+	found, err = r.listPrerequisite(ctx, gatewayClassList, schema.GroupVersionKind{
 		Group:   "gateway.networking.k8s.io",
 		Version: "v1",
 		Kind:    "GatewayClass",
 	})
-	if err := r.List(ctx, gatewayClassList); err != nil {
-		if meta.IsNoMatchError(err) {
-			missingPrereqs = append(missingPrereqs, "GatewayClass CRD")
-		}
-	} else {
-		// Check if the requested GatewayClass exists
+	if err != nil {
+		return err
+	}
+	if !found {
+		missingPrereqs = append(missingPrereqs, "GatewayClass CRD")
+		missingIDs = append(missingIDs, prereqGatewayClassCRD)
+	} else if infScheduler.Spec.Gateway.ExistingGatewayRef == nil {
+		// Check if the requested GatewayClass exists, an existing Gateway already has its class
 		gatewayClassName := getDefaultString(infScheduler.Spec.Gateway.ClassName, "kgateway")
 		found := false
 		for _, item := range gatewayClassList.Items {
@@ -352,26 +1188,49 @@ func (r *InferenceSchedulerReconciler) validatePrerequisites(ctx context.Context
 				break
 			}
 		}
-		if !found {
+		switch {
+		case found:
+			if infScheduler.Annotations[skipGatewayClassCheckAnnotation] == "true" {
+				r.updateCondition(infScheduler, "GatewayClassAvailable", metav1.ConditionTrue, "Found",
+					fmt.Sprintf("GatewayClass '%s' is present", gatewayClassName))
+			}
+		case infScheduler.Annotations[skipGatewayClassCheckAnnotation] == "true":
+			// The GatewayClass may be provisioned just in time, report it without blocking deployment
+			log.FromContext(ctx).Info("GatewayClass not found, continuing because the check is skipped", "gatewayClass", gatewayClassName)
+			r.updateCondition(infScheduler, "GatewayClassAvailable", metav1.ConditionFalse, "NotFoundCheckSkipped",
+				fmt.Sprintf("Warning: GatewayClass '%s' not found; continuing because %s is set", gatewayClassName, skipGatewayClassCheckAnnotation))
+		default:
 			missingPrereqs = append(missingPrereqs, fmt.Sprintf("GatewayClass '%s' (install gateway implementation: kgateway, istio, or gke)", gatewayClassName))
+			missingIDs = append(missingIDs, prereqGatewayClass)
 		}
 	}
 
+	infScheduler.Status.MissingPrerequisites = missingIDs
+
 	if len(missingPrereqs) > 0 {
-		return fmt.Errorf("missing prerequisites: %s. See installation guide: https://github.com/aneeshkp/inference-scheduler-operator/blob/main/README.md#prerequisites", strings.Join(missingPrereqs, "; "))
+		return &missingPrerequisitesError{descriptions: missingPrereqs}
 	}
 
 	return nil
 }
 
-// contains checks if a string slice contains a substring
-func contains(slice []string, substr string) bool {
-	for _, item := range slice {
-		if strings.Contains(item, substr) {
-			return true
+// listPrerequisite lists the objects of a prerequisite kind. It reports false without an error if
+// the kind's CRD is not installed, and returns any other error
+func (r *InferenceSchedulerReconciler) listPrerequisite(ctx context.Context, list *unstructured.UnstructuredList, gvk schema.GroupVersionKind, opts ...client.ListOption) (bool, error) {
+	list.SetGroupVersionKind(gvk)
+	if err := r.List(ctx, list, opts...); err != nil {
+		if isKindMissing(err) {
+			return false, nil
 		}
+		return false, fmt.Errorf("failed to check for %s: %w", gvk.Kind, err)
 	}
-	return false
+	return true, nil
+}
+
+// isKindMissing reports whether a List error means the kind's CRD is not installed. Besides
+// NoMatch errors, a CRD removed after its mapping was cached surfaces as NotFound
+func isKindMissing(err error) bool {
+	return meta.IsNoMatchError(err) || errors.IsNotFound(err)
 }
 
 // isDeploymentReady checks if a deployment is ready
@@ -386,29 +1245,761 @@ func (r *InferenceSchedulerReconciler) isDeploymentReady(ctx context.Context, na
 	return deployment.Status.ReadyReplicas == *deployment.Spec.Replicas, nil
 }
 
+// replicasFromConfigMap reads the model server replica count referenced by ReplicasFrom.
+// It returns nil if an optional reference is missing
+func (r *InferenceSchedulerReconciler) replicasFromConfigMap(ctx context.Context, namespace string, ref *corev1.ConfigMapKeySelector) (*int32, error) {
+	optional := ref.Optional != nil && *ref.Optional
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, configMap); err != nil {
+		if errors.IsNotFound(err) && optional {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	value, ok := configMap.Data[ref.Key]
+	if !ok {
+		if optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ConfigMap %s has no key %s", ref.Name, ref.Key)
+	}
+
+	replicas, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+	if err != nil || replicas < 1 {
+		return nil, fmt.Errorf("ConfigMap %s key %s is not a valid replica count: %q", ref.Name, ref.Key, value)
+	}
+	result := int32(replicas)
+	return &result, nil
+}
+
+// replicasFromIndexer returns the ConfigMap an InferenceScheduler reads its model server
+// replicas from, for the replicasFromIndex field index
+func replicasFromIndexer(obj client.Object) []string {
+	infScheduler, ok := obj.(*llmv1alpha1.InferenceScheduler)
+	if !ok || infScheduler.Spec.ModelServer.ReplicasFrom == nil {
+		return nil
+	}
+	return []string{infScheduler.Spec.ModelServer.ReplicasFrom.Name}
+}
+
+// findSchedulersForReplicasConfigMap maps a ConfigMap to the InferenceSchedulers in its namespace
+// that read their model server replicas from it. The field index keeps every other ConfigMap
+// event from listing the InferenceSchedulers of the namespace
+func (r *InferenceSchedulerReconciler) findSchedulersForReplicasConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	schedulers := &llmv1alpha1.InferenceSchedulerList{}
+	if err := r.List(ctx, schedulers, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{replicasFromIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list InferenceSchedulers for ConfigMap", "configMap", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(schedulers.Items))
+	for _, infScheduler := range schedulers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&infScheduler)})
+	}
+	return requests
+}
+
+// isNamespaceTerminating reports whether the namespace is being deleted. A namespace that is not
+// found has already been deleted, and the InferenceScheduler only remains in the cache until its
+// own deletion is observed, so it is treated the same
+func (r *InferenceSchedulerReconciler) isNamespaceTerminating(ctx context.Context, name string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.reader().Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return namespace.Status.Phase == corev1.NamespaceTerminating || !namespace.DeletionTimestamp.IsZero(), nil
+}
+
+// prerequisiteRecheckInterval returns the configured interval to recheck missing prerequisites
+func (r *InferenceSchedulerReconciler) prerequisiteRecheckInterval() time.Duration {
+	if r.PrerequisiteRecheckInterval <= 0 {
+		return DefaultPrerequisiteRecheckInterval
+	}
+	return r.PrerequisiteRecheckInterval
+}
+
+// finalizer returns the configured finalizer name
+func (r *InferenceSchedulerReconciler) finalizer() string {
+	return getDefaultString(r.FinalizerName, DefaultFinalizerName)
+}
+
+// reader returns the uncached API reader, or the cached client if none is configured
+func (r *InferenceSchedulerReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// validateHFTokenSecret checks that the HuggingFace token secret exists and has the token key
+func (r *InferenceSchedulerReconciler) validateHFTokenSecret(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) error {
+	name := infScheduler.Spec.ModelServer.HFTokenSecretName
+	secret := &corev1.Secret{}
+	if err := r.reader().Get(ctx, types.NamespacedName{Name: name, Namespace: infScheduler.Namespace}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("secret %s not found", name)
+		}
+		return err
+	}
+
+	if len(secret.Data["token"]) == 0 {
+		return fmt.Errorf("secret %s has no token key", name)
+	}
+	return nil
+}
+
+// modelDownloadUnauthorized reports whether a model server container terminated because
+// HuggingFace rejected the token. It relies on the termination message falling back to the log
+func (r *InferenceSchedulerReconciler) modelDownloadUnauthorized(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) (bool, error) {
+	selector := r.buildModelServerDeployment(infScheduler).Spec.Selector.MatchLabels
+	pods := &corev1.PodList{}
+	if err := r.reader().List(ctx, pods, client.InNamespace(infScheduler.Namespace), client.MatchingLabels(selector)); err != nil {
+		return false, err
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if status.Name != "vllm" && status.Name != "preload-model" {
+				continue
+			}
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && isHFAuthFailure(terminated.Message) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// crashLoopMessage describes the first container of the pods matching selector that is in
+// CrashLoopBackOff, with its last termination message, or returns "" if none is. A crash loop
+// right after an image upgrade usually means the image no longer accepts the generated flags
+func (r *InferenceSchedulerReconciler) crashLoopMessage(ctx context.Context, namespace string, selector map[string]string) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.reader().List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(selector)); err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if status.State.Waiting == nil || status.State.Waiting.Reason != "CrashLoopBackOff" {
+				continue
+			}
+			message := fmt.Sprintf("container %s of pod %s is in CrashLoopBackOff", status.Name, pod.Name)
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				detail := strings.TrimSpace(terminated.Message)
+				if detail == "" {
+					detail = fmt.Sprintf("exit code %d (%s)", terminated.ExitCode, terminated.Reason)
+				}
+				message += "; last termination: " + detail
+			}
+			return message, nil
+		}
+	}
+	return "", nil
+}
+
+// reportCrashLoop sets Degraded with reason when a component's pods are crash looping, and clears
+// a Degraded condition it set earlier once they no longer are. Errors are logged only, so they do
+// not hold up the readiness handling
+func (r *InferenceSchedulerReconciler) reportCrashLoop(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler, reason string, selector map[string]string) {
+	message, err := r.crashLoopMessage(ctx, infScheduler.Namespace, selector)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to inspect pods", "reason", reason)
+		return
+	}
+
+	if message != "" {
+		if r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, reason, message) {
+			r.recordEvent(infScheduler, corev1.EventTypeWarning, reason, message)
+		}
+		return
+	}
+	if cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded"); cond != nil && cond.Reason == reason {
+		r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "CrashLoopResolved", "No container is in CrashLoopBackOff")
+	}
+}
+
+// isStatefulSetReady checks if a StatefulSet is ready
+func (r *InferenceSchedulerReconciler) isStatefulSetReady(ctx context.Context, namespace, name string) (bool, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, statefulSet)
+	if err != nil {
+		return false, err
+	}
+
+	return statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas, nil
+}
+
+// modelServerReadyReplicas returns the ready replicas of the model server Deployment or StatefulSet
+func (r *InferenceSchedulerReconciler) modelServerReadyReplicas(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) (int32, error) {
+	key := types.NamespacedName{Name: fmt.Sprintf("%s-vllm", infScheduler.Name), Namespace: infScheduler.Namespace}
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		statefulSet := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, key, statefulSet); err != nil {
+			return 0, err
+		}
+		return statefulSet.Status.ReadyReplicas, nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, key, deployment); err != nil {
+		return 0, err
+	}
+	return deployment.Status.ReadyReplicas, nil
+}
+
+// deleteStaleModelServerWorkload deletes the model server Deployment, or the StatefulSet and its
+// headless Service, when the InferenceScheduler has switched to the other workload kind. The
+// HorizontalPodAutoscaler is deleted once autoscaling is disabled, so it stops scaling the workload
+func (r *InferenceSchedulerReconciler) deleteStaleModelServerWorkload(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) error {
+	key := types.NamespacedName{Name: fmt.Sprintf("%s-vllm", infScheduler.Name), Namespace: infScheduler.Namespace}
+
+	var stale []client.Object
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		stale = append(stale, &appsv1.Deployment{})
+	} else {
+		stale = append(stale, &appsv1.StatefulSet{}, &corev1.Service{})
+	}
+	if infScheduler.Spec.ModelServer.Autoscaling == nil {
+		stale = append(stale, &autoscalingv2.HorizontalPodAutoscaler{})
+	}
+
+	for _, obj := range stale {
+		objKey := key
+		if _, ok := obj.(*corev1.Service); ok {
+			objKey.Name = fmt.Sprintf("%s-vllm-headless", infScheduler.Name)
+		}
+		if err := r.deleteOwned(ctx, infScheduler, objKey, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcilePodDisruptionBudget creates or updates a component's PodDisruptionBudget, or deletes the
+// one named name once the component no longer wants one
+func (r *InferenceSchedulerReconciler) reconcilePodDisruptionBudget(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler, name string, pdb *policyv1.PodDisruptionBudget) error {
+	if pdb != nil {
+		return r.createOrUpdate(ctx, pdb, infScheduler)
+	}
+	key := types.NamespacedName{Name: name, Namespace: infScheduler.Namespace}
+	return r.deleteOwned(ctx, infScheduler, key, &policyv1.PodDisruptionBudget{})
+}
+
+// deleteOwned deletes the object at key if it exists and is owned by the InferenceScheduler.
+// Objects that this InferenceScheduler does not own are left alone
+func (r *InferenceSchedulerReconciler) deleteOwned(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler, key types.NamespacedName, obj client.Object) error {
+	if err := r.Get(ctx, key, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !isOwnedBy(obj, infScheduler) {
+		return nil
+	}
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isOwnedBy reports whether obj has an owner reference to the InferenceScheduler, whether it is a
+// controller reference or not, see OwnerReferences
+func isOwnedBy(obj client.Object, infScheduler *llmv1alpha1.InferenceScheduler) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == infScheduler.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayListenerConflicts returns the listener conflicts of the desired Gateway. When a Gateway
+// of the same name exists that this InferenceScheduler does not control, its listeners are
+// checked against the desired ones too
+func (r *InferenceSchedulerReconciler) gatewayListenerConflicts(ctx context.Context, gateway *unstructured.Unstructured, infScheduler *llmv1alpha1.InferenceScheduler) ([]string, error) {
+	// The built Gateway holds int32 ports, which NestedSlice cannot deep copy
+	desired, _, _ := unstructured.NestedFieldNoCopy(gateway.Object, "spec", "listeners")
+	listeners, _ := desired.([]interface{})
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gateway.GroupVersionKind())
+	err := r.Get(ctx, client.ObjectKeyFromObject(gateway), existing)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get Gateway %s: %w", gateway.GetName(), err)
+	case !metav1.IsControlledBy(existing, infScheduler):
+		existingListeners, _, _ := unstructured.NestedSlice(existing.Object, "spec", "listeners")
+		listeners = append(existingListeners, listeners...)
+	}
+
+	return validateGatewayListeners(listeners), nil
+}
+
+// existingGatewayFound reports whether the Gateway referenced by existingGatewayRef exists
+func (r *InferenceSchedulerReconciler) existingGatewayFound(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) (bool, error) {
+	key := gatewayKey(infScheduler)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"})
+	err := r.Get(ctx, key, existing)
+	switch {
+	case errors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to get Gateway %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// isInferencePoolAccepted reports whether a parent Gateway has accepted the InferencePool.
+// If not, the returned reason explains why
+func (r *InferenceSchedulerReconciler) isInferencePoolAccepted(ctx context.Context, pool *unstructured.Unstructured) (bool, string, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(pool.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pool), existing); err != nil {
+		return false, "", err
+	}
+
+	reason := "No Gateway has reported status for the InferencePool yet"
+	parents, _, _ := unstructured.NestedSlice(existing.Object, "status", "parents")
+	for _, parent := range parents {
+		parentMap, ok := parent.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(parentMap, "conditions")
+		for _, condition := range conditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok || conditionMap["type"] != "Accepted" {
+				continue
+			}
+			if conditionMap["status"] == string(metav1.ConditionTrue) {
+				return true, "", nil
+			}
+			if message, ok := conditionMap["message"].(string); ok && message != "" {
+				reason = fmt.Sprintf("InferencePool is not accepted: %s", message)
+			}
+		}
+	}
+	return false, reason, nil
+}
+
+// holdRollout reports whether the update of held must wait for first to finish rolling out its
+// desired images. Both are Deployments or StatefulSets. Only updates are held; a workload that
+// does not exist yet is created right away
+func (r *InferenceSchedulerReconciler) holdRollout(ctx context.Context, held, first client.Object) (bool, error) {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(held), held.DeepCopyObject().(client.Object)); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// A paused workload does not roll out its images until it is unpaused, which must not block
+	// the other component
+	if workloadPaused(first) {
+		return false, nil
+	}
+
+	existing := first.DeepCopyObject().(client.Object)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(first), existing); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// The image update of first has not been applied yet. Containers are matched by name, so a
+	// reordered or added sidecar is not mistaken for a new image
+	existingImages := map[string]string{}
+	for _, container := range workloadPodTemplate(existing).Spec.Containers {
+		existingImages[container.Name] = container.Image
+	}
+	for _, container := range workloadPodTemplate(first).Spec.Containers {
+		if image, ok := existingImages[container.Name]; ok && image != container.Image {
+			return true, nil
+		}
+	}
+
+	// Only pods still running a previous image hold the update. Other template changes, and pods
+	// that stay unavailable on the current images, e.g. pending on a GPU, do not
+	if workloadUpdated(existing) {
+		return false, nil
+	}
+	return r.runsPreviousImages(ctx, existing)
+}
+
+// workloadPodTemplate returns the pod template of a Deployment or StatefulSet
+func workloadPodTemplate(obj client.Object) *corev1.PodTemplateSpec {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Template
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Template
+	}
+	return &corev1.PodTemplateSpec{}
+}
+
+// workloadPaused reports whether a Deployment is paused, or a StatefulSet keeps every pod on its
+// revision with a partition, see PauseRollout
+func workloadPaused(obj client.Object) bool {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return workload.Spec.Paused
+	case *appsv1.StatefulSet:
+		rollingUpdate := workload.Spec.UpdateStrategy.RollingUpdate
+		return rollingUpdate != nil && rollingUpdate.Partition != nil && *rollingUpdate.Partition > 0 &&
+			(workload.Spec.Replicas == nil || *rollingUpdate.Partition >= *workload.Spec.Replicas)
+	}
+	return false
+}
+
+// workloadUpdated reports whether every pod of a Deployment or StatefulSet was created from the
+// current template. Availability is not required
+func workloadUpdated(obj client.Object) bool {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		status := workload.Status
+		return status.ObservedGeneration >= workload.Generation &&
+			status.UpdatedReplicas == *workload.Spec.Replicas &&
+			status.Replicas == status.UpdatedReplicas
+	case *appsv1.StatefulSet:
+		status := workload.Status
+		return status.ObservedGeneration >= workload.Generation &&
+			(status.CurrentRevision == status.UpdateRevision || status.UpdatedReplicas == *workload.Spec.Replicas)
+	}
+	return true
+}
+
+// runsPreviousImages reports whether a pod of a Deployment or StatefulSet runs a container image
+// other than the one of its current template. Containers are matched by name
+func (r *InferenceSchedulerReconciler) runsPreviousImages(ctx context.Context, obj client.Object) (bool, error) {
+	var selector *metav1.LabelSelector
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		selector = workload.Spec.Selector
+	case *appsv1.StatefulSet:
+		selector = workload.Spec.Selector
+	}
+	if selector == nil {
+		return false, nil
+	}
+
+	images := map[string]string{}
+	for _, container := range workloadPodTemplate(obj).Spec.Containers {
+		images[container.Name] = container.Image
+	}
+	pods := &corev1.PodList{}
+	if err := r.reader().List(ctx, pods, client.InNamespace(obj.GetNamespace()), client.MatchingLabels(selector.MatchLabels)); err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if image, ok := images[container.Name]; ok && image != container.Image {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// modelServerWait returns when to check on a model server that is not ready. Readiness changes are
+// watched, the requeue only catches missed ones, so a model that takes minutes to load is not polled
+// at a constant rate, and polling slows down once the readiness deadline has passed
+func (r *InferenceSchedulerReconciler) modelServerWait(name types.NamespacedName, deadlineExceeded bool) time.Duration {
+	if deadlineExceeded {
+		return 5 * time.Minute
+	}
+	return r.backoff.next(name, waitModelServerReady, 30*time.Second)
+}
+
+// readinessDeadlineExceeded reports whether the model server has been not ready for longer than
+// the configured ReadinessDeadline. The ModelServerReady condition's transition time marks when
+// the model server was last seen going not ready.
+func (r *InferenceSchedulerReconciler) readinessDeadlineExceeded(infScheduler *llmv1alpha1.InferenceScheduler) bool {
+	deadline := infScheduler.Spec.ModelServer.ReadinessDeadline
+	if deadline == nil {
+		return false
+	}
+
+	cond := meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerReady")
+	if cond == nil || cond.Status != metav1.ConditionFalse {
+		return false
+	}
+
+	return time.Since(cond.LastTransitionTime.Time) > deadline.Duration
+}
+
 // createOrUpdate creates or updates a Kubernetes resource
 func (r *InferenceSchedulerReconciler) createOrUpdate(ctx context.Context, obj client.Object, owner client.Object) error {
 	key := client.ObjectKeyFromObject(obj)
 	existing := obj.DeepCopyObject().(client.Object)
+	setManagedMetadata(obj)
+	setAppliedHash(obj)
+	setLastApplied(obj)
 
 	err := r.Get(ctx, key, existing)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Set owner reference
-			if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
+			if err := r.setOwnerReference(owner, obj); err != nil {
 				return err
 			}
-			return r.Create(ctx, obj)
+			if err := r.Create(ctx, obj); err != nil {
+				return err
+			}
+			r.recordCreated(owner, obj)
+			return nil
 		}
 		return err
 	}
 
 	// Update existing resource
 	obj.SetResourceVersion(existing.GetResourceVersion())
-	if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
+	// Owner references set by others are kept, so a child controlled by another controller fails
+	// with an AlreadyOwnedError instead of being adopted
+	obj.SetOwnerReferences(existing.GetOwnerReferences())
+	if err := r.setOwnerReference(owner, obj); err != nil {
+		return err
+	}
+	skip, err := keepIgnoredDrift(obj, existing)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return nil
+	}
+	keepAutoscaledReplicas(obj, existing)
+	keepBoundClaim(obj, existing)
+	// Skip the update, and the resourceVersion bump it causes for watchers, if nothing changed
+	if upToDate(obj, existing) {
+		return nil
+	}
+	return r.patchChild(ctx, obj, existing)
+}
+
+// keepAutoscaledReplicas carries the current replica count over to a desired workload that leaves it
+// to a HorizontalPodAutoscaler. Updating with no replicas would reset the workload to one replica
+func keepAutoscaledReplicas(desired, existing client.Object) {
+	switch desired := desired.(type) {
+	case *appsv1.Deployment:
+		if desired.Spec.Replicas == nil {
+			desired.Spec.Replicas = existing.(*appsv1.Deployment).Spec.Replicas
+		}
+	case *appsv1.StatefulSet:
+		if desired.Spec.Replicas == nil {
+			desired.Spec.Replicas = existing.(*appsv1.StatefulSet).Spec.Replicas
+		}
+	}
+}
+
+// keepIgnoredDrift honors the ignoreDriftAnnotation of the existing child. It reports whether the
+// whole object is opted out, and otherwise copies the opted out fields from existing to desired.
+// The opt-out wins over the InferenceScheduler spec, except for the operator's own labels and
+// annotations and the opt-out annotation itself, which are always kept
+func keepIgnoredDrift(desired, existing client.Object) (bool, error) {
+	value := strings.TrimSpace(existing.GetAnnotations()[ignoreDriftAnnotation])
+	if value == "" {
+		return false, nil
+	}
+	if value == "true" {
+		return true, nil
+	}
+
+	// Built unstructured objects hold int32 values, which the converter cannot deep copy
+	var desiredContent map[string]interface{}
+	if u, ok := desired.(*unstructured.Unstructured); ok {
+		desiredContent = u.Object
+	} else {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+		if err != nil {
+			return false, err
+		}
+		desiredContent = content
+	}
+	existingContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return false, err
+	}
+
+	labels, annotations := desired.GetLabels(), desired.GetAnnotations()
+	for _, path := range strings.Split(value, ",") {
+		fields := strings.Split(strings.TrimSpace(path), ".")
+		if fields[0] == "" || fields[0] == "apiVersion" || fields[0] == "kind" {
+			continue
+		}
+		current, found, err := unstructured.NestedFieldCopy(existingContent, fields...)
+		if err != nil {
+			continue
+		}
+		if found {
+			if err := unstructured.SetNestedField(desiredContent, current, fields...); err != nil {
+				continue
+			}
+		} else {
+			unstructured.RemoveNestedField(desiredContent, fields...)
+		}
+	}
+
+	if _, ok := desired.(*unstructured.Unstructured); !ok {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(desiredContent, desired); err != nil {
+			return false, err
+		}
+	}
+
+	desired.SetLabels(mergeStringMaps(desired.GetLabels(), map[string]string{managedByLabel: labels[managedByLabel]}))
+	desired.SetAnnotations(mergeStringMaps(desired.GetAnnotations(), map[string]string{
+		operatorVersionAnnotation: annotations[operatorVersionAnnotation],
+		appliedHashAnnotation:     annotations[appliedHashAnnotation],
+		lastAppliedAnnotation:     annotations[lastAppliedAnnotation],
+		ignoreDriftAnnotation:     value,
+	}))
+	return false, nil
+}
+
+// keepBoundClaim carries the fields that are set when a PersistentVolumeClaim is bound over to the
+// desired claim, since the claim spec is immutable except for the requested storage
+func keepBoundClaim(desired, existing client.Object) {
+	claim, ok := desired.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+	current := existing.(*corev1.PersistentVolumeClaim)
+	claim.Spec.VolumeName = current.Spec.VolumeName
+	claim.Spec.VolumeMode = current.Spec.VolumeMode
+	if claim.Spec.StorageClassName == nil {
+		claim.Spec.StorageClassName = current.Spec.StorageClassName
+	}
+}
+
+// maxModelServerReplicas returns the most model server replicas that can run at once
+func maxModelServerReplicas(infScheduler *llmv1alpha1.InferenceScheduler) int32 {
+	if autoscaling := infScheduler.Spec.ModelServer.Autoscaling; autoscaling != nil {
+		return autoscaling.MaxReplicas
+	}
+	return infScheduler.Spec.ModelServer.Replicas
+}
+
+// setAppliedHash annotates obj with the hash of its desired state
+func setAppliedHash(obj client.Object) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return
+	}
+	sum := sha256.Sum256(data)
+	obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), map[string]string{appliedHashAnnotation: hex.EncodeToString(sum[:])}))
+}
+
+// setLastApplied annotates obj with its desired content. Status is left out, it is never applied
+func setLastApplied(obj client.Object) {
+	content, err := comparableContent(obj)
+	if err != nil {
+		return
+	}
+	delete(content, "status")
+	data, err := json.Marshal(content)
+	if err != nil {
+		return
+	}
+	obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), map[string]string{lastAppliedAnnotation: string(data)}))
+}
+
+// patchChild brings existing to desired with a three-way JSON merge patch, like kubectl apply: fields
+// desired sets are written, fields dropped since the last applied content are removed, and fields
+// only others set, such as their annotations and server-side defaults, are kept. A child written
+// before the last applied content was recorded has nothing removed
+func (r *InferenceSchedulerReconciler) patchChild(ctx context.Context, desired, existing client.Object) error {
+	patch, err := childPatch(desired, existing)
+	if err != nil {
 		return err
 	}
-	return r.Update(ctx, obj)
+	if string(patch) == "{}" {
+		return nil
+	}
+	return r.Patch(ctx, desired, client.RawPatch(types.MergePatchType, patch))
+}
+
+// childPatch returns the three-way JSON merge patch from existing to desired, based on the
+// content recorded in the last-applied annotation
+func childPatch(desired, existing client.Object) ([]byte, error) {
+	modified, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+	current, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	original := []byte(existing.GetAnnotations()[lastAppliedAnnotation])
+
+	patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch for %s: %w", desired.GetName(), err)
+	}
+	return patch, nil
+}
+
+// upToDate reports whether existing needs no update to match desired. The applied hash catches
+// changes of the desired state, including removed fields. The semantic comparison catches edits
+// made to the object by others; fields that desired leaves unset, such as server-side defaults,
+// are ignored.
+func upToDate(desired, existing client.Object) bool {
+	if desired.GetAnnotations()[appliedHashAnnotation] != existing.GetAnnotations()[appliedHashAnnotation] {
+		return false
+	}
+
+	desiredContent, err := comparableContent(desired)
+	if err != nil {
+		return false
+	}
+	existingContent, err := comparableContent(existing)
+	if err != nil {
+		return false
+	}
+
+	for key, value := range desiredContent {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			desiredMeta, _ := value.(map[string]interface{})
+			existingMeta, _ := existingContent["metadata"].(map[string]interface{})
+			for _, field := range []string{"labels", "annotations", "ownerReferences"} {
+				if !equality.Semantic.DeepDerivative(desiredMeta[field], existingMeta[field]) {
+					return false
+				}
+			}
+		default:
+			if !equality.Semantic.DeepDerivative(value, existingContent[key]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// comparableContent returns the JSON content of obj with numbers decoded as int64 or float64,
+// so typed and unstructured objects compare alike
+func comparableContent(obj client.Object) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	content := map[string]interface{}{}
+	if err := utiljson.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
 }
 
 // createOrUpdateUnstructured creates or updates an unstructured resource
@@ -416,34 +2007,89 @@ func (r *InferenceSchedulerReconciler) createOrUpdateUnstructured(ctx context.Co
 	key := client.ObjectKeyFromObject(obj)
 	existing := &unstructured.Unstructured{}
 	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	setManagedMetadata(obj)
+	setAppliedHash(obj)
+	setLastApplied(obj)
 
 	err := r.Get(ctx, key, existing)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Set owner reference
-			if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
+			if err := r.setOwnerReference(owner, obj); err != nil {
 				return err
 			}
-			return r.Create(ctx, obj)
+			if err := r.Create(ctx, obj); err != nil {
+				return err
+			}
+			r.recordCreated(owner, obj)
+			return nil
 		}
 		return err
 	}
 
 	// Update existing resource
 	obj.SetResourceVersion(existing.GetResourceVersion())
-	if err := ctrl.SetControllerReference(owner, obj, r.Scheme); err != nil {
+	// Owner references set by others are kept, so a child controlled by another controller fails
+	// with an AlreadyOwnedError instead of being adopted
+	obj.SetOwnerReferences(existing.GetOwnerReferences())
+	if err := r.setOwnerReference(owner, obj); err != nil {
+		return err
+	}
+	skip, err := keepIgnoredDrift(obj, existing)
+	if err != nil {
 		return err
 	}
-	return r.Update(ctx, obj)
+	if skip {
+		return nil
+	}
+	// Skip the update, and the resourceVersion bump it causes for watchers, if nothing changed
+	if upToDate(obj, existing) {
+		return nil
+	}
+	return r.patchChild(ctx, obj, existing)
 }
 
-// updateCondition updates or adds a condition to the status
+// setOwnerReference sets the owner reference on obj according to the OwnerReferences policy for
+// its kind, defaulting to a controller reference that blocks owner deletion
+func (r *InferenceSchedulerReconciler) setOwnerReference(owner, obj client.Object) error {
+	controller, blockOwnerDeletion := true, true
+
+	if infScheduler, ok := owner.(*llmv1alpha1.InferenceScheduler); ok {
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return err
+		}
+		for _, policy := range infScheduler.Spec.OwnerReferences {
+			if policy.Kind != gvk.Kind {
+				continue
+			}
+			controller = getDefaultBool(policy.Controller, true)
+			blockOwnerDeletion = getDefaultBool(policy.BlockOwnerDeletion, true)
+		}
+	}
+
+	if controller {
+		return ctrl.SetControllerReference(owner, obj, r.Scheme, controllerutil.WithBlockOwnerDeletion(blockOwnerDeletion))
+	}
+	// A plain owner reference can be added next to another controller, which would then be fought
+	// over, so a child controlled by another owner is refused like with a controller reference
+	if ref := metav1.GetControllerOf(obj); ref != nil && ref.UID != owner.GetUID() {
+		return &controllerutil.AlreadyOwnedError{Object: obj, Owner: *ref}
+	}
+	return controllerutil.SetOwnerReference(owner, obj, r.Scheme, controllerutil.WithBlockOwnerDeletion(blockOwnerDeletion))
+}
+
+// updateCondition updates or adds a condition to the status. Reports whether the condition's
+// status changed, i.e. whether the transition is worth an Event
 func (r *InferenceSchedulerReconciler) updateCondition(
 	infScheduler *llmv1alpha1.InferenceScheduler,
 	conditionType string,
 	status metav1.ConditionStatus,
 	reason, message string,
-) {
+) bool {
+	previous := meta.FindStatusCondition(infScheduler.Status.Conditions, conditionType)
+	transitioned := previous == nil || previous.Status != status
+
 	condition := metav1.Condition{
 		Type:               conditionType,
 		Status:             status,
@@ -454,6 +2100,59 @@ func (r *InferenceSchedulerReconciler) updateCondition(
 	}
 
 	meta.SetStatusCondition(&infScheduler.Status.Conditions, condition)
+	return transitioned
+}
+
+// aggregateConditions sets the Ready condition from the component conditions, and Degraded when
+// some but not all components are ready. A Degraded condition raised for a specific reason, such as
+// an ownership conflict, is left alone. It returns whether any condition changed
+func (r *InferenceSchedulerReconciler) aggregateConditions(infScheduler *llmv1alpha1.InferenceScheduler) bool {
+	before := make([]metav1.Condition, len(infScheduler.Status.Conditions))
+	copy(before, infScheduler.Status.Conditions)
+
+	var notReady []string
+	for _, component := range componentConditions {
+		if !meta.IsStatusConditionTrue(infScheduler.Status.Conditions, component) {
+			notReady = append(notReady, component)
+		}
+	}
+
+	if len(notReady) == 0 {
+		r.updateCondition(infScheduler, "Ready", metav1.ConditionTrue, "AllComponentsReady", "All components are ready")
+	} else {
+		r.updateCondition(infScheduler, "Ready", metav1.ConditionFalse, "ComponentsNotReady", "Not ready: "+strings.Join(notReady, ", "))
+	}
+
+	degraded := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded")
+	if degraded == nil || degraded.Status != metav1.ConditionTrue || degraded.Reason == "PartiallyReady" {
+		switch len(notReady) {
+		case 0:
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "AllComponentsReady", "All components are ready")
+		case len(componentConditions):
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "NoComponentsReady", "No component is ready yet")
+		default:
+			r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, "PartiallyReady", "Not ready: "+strings.Join(notReady, ", "))
+		}
+	}
+
+	return !reflect.DeepEqual(before, infScheduler.Status.Conditions)
+}
+
+// recordEvent emits an Event on the InferenceScheduler, if the reconciler has a recorder
+func (r *InferenceSchedulerReconciler) recordEvent(obj runtime.Object, eventType, reason, message string) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(obj, eventType, reason, message)
+}
+
+// recordCreated emits an Event on the owner for a child resource it has just created
+func (r *InferenceSchedulerReconciler) recordCreated(owner, obj client.Object) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+	r.recordEvent(owner, corev1.EventTypeNormal, "Created", fmt.Sprintf("Created %s %s", kind, obj.GetName()))
 }
 
 // sanitizeName sanitizes a string to be a valid Kubernetes name
@@ -497,16 +2196,72 @@ func getDefaultFloat64(value *float64, defaultValue float64) float64 {
 	return defaultValue
 }
 
+// getDefaultBool returns the value if not nil, otherwise returns default
+func getDefaultBool(value *bool, defaultValue bool) bool {
+	if value != nil {
+		return *value
+	}
+	return defaultValue
+}
+
+// mergeStringMaps returns a new map with the entries of all maps, later maps taking precedence.
+// Returns nil if all maps are empty.
+func mergeStringMaps(maps ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *InferenceSchedulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	watches := &childWatches{mgr: mgr, watched: map[schema.GroupVersionKind]bool{}}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &llmv1alpha1.InferenceScheduler{},
+		replicasFromIndex, replicasFromIndexer); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&llmv1alpha1.InferenceScheduler{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&corev1.Service{}).
-		Owns(&corev1.ServiceAccount{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&rbacv1.Role{}).
-		Owns(&rbacv1.RoleBinding{}).
-		Named("inferencescheduler").
-		Complete(r)
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.findSchedulersForReplicasConfigMap)).
+		WatchesMetadata(crdMetadata(), handler.EnqueueRequestsFromMapFunc(r.findSchedulersForCRD))
+
+	for _, kind := range typedChildKinds {
+		b = b.Owns(kind)
+	}
+
+	// Watch the unstructured children so that edits or deletions are repaired immediately
+	// rather than on the next periodic requeue. Kinds whose CRDs are not installed are skipped,
+	// otherwise the manager would fail to start; they are picked up by childWatches.ensure
+	// once the CRDs appear.
+	for _, gvk := range unstructuredChildGVKs {
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			mgr.GetLogger().Info("Deferring watch until CRD is installed", "kind", gvk.String())
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		b = b.Owns(obj)
+		watches.watched[gvk] = true
+	}
+
+	c, err := b.Named("inferencescheduler").
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	watches.controller = c
+	r.watches = watches
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("inferencescheduler-controller")
+	}
+	return nil
 }
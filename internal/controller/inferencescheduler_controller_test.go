@@ -18,11 +18,26 @@ package controller
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -82,3 +97,1604 @@ var _ = Describe("InferenceScheduler Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Prerequisite validation", func() {
+	var (
+		infScheduler *llmv1alpha1.InferenceScheduler
+		listErrors   map[string]error
+		r            *InferenceSchedulerReconciler
+	)
+
+	BeforeEach(func() {
+		infScheduler = &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-scheduler", Namespace: "default"},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{
+					ModelName:         "Qwen/Qwen2.5-0.5B-Instruct",
+					HFTokenSecretName: "hf-token",
+				},
+			},
+		}
+		listErrors = map[string]error{}
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(infScheduler.DeepCopy(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}).
+			WithStatusSubresource(&llmv1alpha1.InferenceScheduler{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					unstructuredList, ok := list.(*unstructured.UnstructuredList)
+					if !ok {
+						return c.List(ctx, list, opts...)
+					}
+					// The prerequisite kinds are served by the fake API server with one GatewayClass
+					kind := strings.TrimSuffix(unstructuredList.GetKind(), "List")
+					if err := listErrors[kind]; err != nil {
+						return err
+					}
+					if kind == "GatewayClass" {
+						gatewayClass := unstructured.Unstructured{}
+						gatewayClass.SetName("kgateway")
+						unstructuredList.Items = []unstructured.Unstructured{gatewayClass}
+					}
+					return nil
+				},
+			}).
+			Build()
+		r = &InferenceSchedulerReconciler{Client: fakeClient, Scheme: scheme}
+	})
+
+	noMatch := func(kind string) error {
+		return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: kind}}
+	}
+
+	It("should record every prerequisite as present when all are served", func() {
+		Expect(r.validatePrerequisites(context.Background(), infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.MissingPrerequisites).To(BeEmpty())
+	})
+
+	DescribeTable("should record a kind whose CRD is not installed as missing",
+		func(kind, id string) {
+			listErrors[kind] = noMatch(kind)
+
+			err := r.validatePrerequisites(context.Background(), infScheduler)
+			Expect(err).To(BeAssignableToTypeOf(&missingPrerequisitesError{}))
+			Expect(infScheduler.Status.MissingPrerequisites).To(ConsistOf(id))
+		},
+		Entry("Gateway", "Gateway", prereqGatewayAPI),
+		Entry("HTTPRoute", "HTTPRoute", prereqHTTPRoute),
+		Entry("InferencePool", "InferencePool", prereqGIE),
+		Entry("GatewayClass", "GatewayClass", prereqGatewayClassCRD),
+	)
+
+	DescribeTable("should return transient API errors instead of judging the prerequisites",
+		func(kind string) {
+			listErrors[kind] = errors.NewServiceUnavailable("etcd leader changed")
+
+			err := r.validatePrerequisites(context.Background(), infScheduler)
+			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(BeAssignableToTypeOf(&missingPrerequisitesError{}))
+			Expect(errors.IsServiceUnavailable(err)).To(BeTrue())
+		},
+		Entry("Gateway", "Gateway"),
+		Entry("HTTPRoute", "HTTPRoute"),
+		Entry("InferencePool", "InferencePool"),
+		Entry("GatewayClass", "GatewayClass"),
+	)
+
+	It("should requeue with an error when the API server is unavailable", func() {
+		listErrors["Gateway"] = errors.NewServiceUnavailable("etcd leader changed")
+
+		key := types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+		Expect(updated.Status.Phase).NotTo(Equal("PrerequisitesMissing"))
+	})
+
+	It("should report missing prerequisites and check again later", func() {
+		listErrors["InferencePool"] = noMatch("InferencePool")
+
+		key := types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(60 * time.Second))
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal("PrerequisitesMissing"))
+		Expect(updated.Status.MissingPrerequisites).To(ConsistOf(prereqGIE))
+	})
+
+	It("should check missing prerequisites less often until they are installed", func() {
+		listErrors["InferencePool"] = noMatch("InferencePool")
+
+		key := types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		var intervals []time.Duration
+		for range 5 {
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			intervals = append(intervals, result.RequeueAfter)
+		}
+		Expect(intervals).To(Equal([]time.Duration{60 * time.Second, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}))
+
+		// Once the prerequisites pass, the next time they go missing starts over. Conflicting EPP
+		// ports stop the reconcile before any child is created
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+		updated.Spec.EndpointPicker.GRPCPort = 9003
+		Expect(r.Update(context.Background(), updated)).To(Succeed())
+		delete(listErrors, "InferencePool")
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		listErrors["InferencePool"] = noMatch("InferencePool")
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(60 * time.Second))
+	})
+
+	It("should report prerequisites removed after deployment as Degraded and recover once they return", func() {
+		key := types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		deployed := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(context.Background(), key, deployed)).To(Succeed())
+		deployed.Status.Phase = "Ready"
+		deployed.Status.PrerequisitesValidated = true
+		Expect(r.Status().Update(context.Background(), deployed)).To(Succeed())
+
+		listErrors["InferencePool"] = noMatch("InferencePool")
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(60 * time.Second))
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+		degraded := meta.FindStatusCondition(updated.Status.Conditions, "Degraded")
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("PrerequisitesRemoved"))
+		Expect(degraded.Message).To(ContainSubstring("existing resources are left running"))
+		// Nothing was applied while the prerequisites are missing
+		Expect(errors.IsNotFound(r.Get(context.Background(),
+			types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+
+		// Conflicting EPP ports stop the next reconcile before any child is created
+		updated.Spec.EndpointPicker.GRPCPort = 9003
+		Expect(r.Update(context.Background(), updated)).To(Succeed())
+		delete(listErrors, "InferencePool")
+		_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+		Expect(updated.Status.PrerequisitesValidated).To(BeTrue())
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, "Degraded").Reason).NotTo(Equal("PrerequisitesRemoved"))
+	})
+
+	It("should recheck missing prerequisites at the configured interval up to the configured maximum", func() {
+		listErrors["InferencePool"] = noMatch("InferencePool")
+		r.PrerequisiteRecheckInterval = 10 * time.Second
+		r.MaxPrerequisiteRechecks = 3
+
+		key := types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		var intervals []time.Duration
+		for range 5 {
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			intervals = append(intervals, result.RequeueAfter)
+		}
+		Expect(intervals).To(Equal([]time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 0, 0}))
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+		cond := meta.FindStatusCondition(updated.Status.Conditions, "PrerequisitesValidated")
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Message).To(ContainSubstring("Stopped rechecking after 3 attempts"))
+	})
+
+	It("should enqueue the schedulers waiting for prerequisites when a prerequisite CRD is installed", func() {
+		listErrors["InferencePool"] = noMatch("InferencePool")
+		key := types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		crd := crdMetadata()
+		crd.SetName("inferencepools.inference.networking.k8s.io")
+		Expect(r.findSchedulersForCRD(context.Background(), crd)).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+
+		crd.SetName("servicemonitors.monitoring.coreos.com")
+		Expect(r.findSchedulersForCRD(context.Background(), crd)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Condition aggregation", func() {
+	var (
+		infScheduler *llmv1alpha1.InferenceScheduler
+		r            *InferenceSchedulerReconciler
+	)
+
+	BeforeEach(func() {
+		infScheduler = &llmv1alpha1.InferenceScheduler{}
+		r = &InferenceSchedulerReconciler{}
+	})
+
+	setReady := func(components ...string) {
+		for _, component := range components {
+			r.updateCondition(infScheduler, component, metav1.ConditionTrue, "Ready", "")
+		}
+	}
+
+	DescribeTable("should summarize the component conditions",
+		func(ready []string, readyStatus, degradedStatus metav1.ConditionStatus, degradedReason string) {
+			setReady(ready...)
+			Expect(r.aggregateConditions(infScheduler)).To(BeTrue())
+
+			Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Ready").Status).To(Equal(readyStatus))
+			degraded := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded")
+			Expect(degraded.Status).To(Equal(degradedStatus))
+			Expect(degraded.Reason).To(Equal(degradedReason))
+
+			// Nothing changes without a component changing
+			Expect(r.aggregateConditions(infScheduler)).To(BeFalse())
+		},
+		Entry("all ready", componentConditions, metav1.ConditionTrue, metav1.ConditionFalse, "AllComponentsReady"),
+		Entry("partially ready", []string{"PrerequisitesValidated", "ModelServerReady"}, metav1.ConditionFalse, metav1.ConditionTrue, "PartiallyReady"),
+		Entry("none ready", []string{}, metav1.ConditionFalse, metav1.ConditionFalse, "NoComponentsReady"),
+	)
+
+	It("should list the components that are not ready", func() {
+		setReady("PrerequisitesValidated", "ModelServerReady", "GatewayReady")
+		r.updateCondition(infScheduler, "EPPReady", metav1.ConditionFalse, "NotReady", "")
+		r.aggregateConditions(infScheduler)
+
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded").Message).To(Equal("Not ready: EPPReady, InferencePoolReady"))
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Ready").Message).To(Equal("Not ready: EPPReady, InferencePoolReady"))
+	})
+
+	It("should keep a Degraded condition raised for a specific reason", func() {
+		setReady(componentConditions...)
+		r.updateCondition(infScheduler, "Degraded", metav1.ConditionTrue, "OwnershipConflict", "conflict")
+		r.aggregateConditions(infScheduler)
+
+		Expect(meta.IsStatusConditionTrue(infScheduler.Status.Conditions, "Ready")).To(BeTrue())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded").Reason).To(Equal("OwnershipConflict"))
+
+		// Once the conflict is resolved the aggregate takes over again
+		r.updateCondition(infScheduler, "Degraded", metav1.ConditionFalse, "OwnershipResolved", "")
+		r.updateCondition(infScheduler, "EPPReady", metav1.ConditionFalse, "NotReady", "")
+		r.aggregateConditions(infScheduler)
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded").Reason).To(Equal("PartiallyReady"))
+	})
+})
+
+var _ = Describe("Replicas from a ConfigMap", func() {
+	var r *InferenceSchedulerReconciler
+
+	scheduler := func(name, configMap string) *llmv1alpha1.InferenceScheduler {
+		infScheduler := &llmv1alpha1.InferenceScheduler{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if configMap != "" {
+			infScheduler.Spec.ModelServer.ReplicasFrom = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap},
+				Key:                  "replicas",
+			}
+		}
+		return infScheduler
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+		r = &InferenceSchedulerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithIndex(&llmv1alpha1.InferenceScheduler{}, replicasFromIndex, replicasFromIndexer).
+				WithObjects(
+					scheduler("capacity-a", "capacity"),
+					scheduler("capacity-b", "capacity"),
+					scheduler("other", "other-capacity"),
+					scheduler("static", ""),
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "capacity", Namespace: "default"},
+						Data: map[string]string{
+							"replicas":     " 3\n",
+							"zero":         "0",
+							"negative":     "-2",
+							"not-a-number": "three",
+						},
+					},
+				).
+				Build(),
+			Scheme: scheme,
+		}
+	})
+
+	selector := func(name, key string, optional bool) *corev1.ConfigMapKeySelector {
+		return &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+			Optional:             &optional,
+		}
+	}
+
+	It("should read a replica count surrounded by whitespace", func() {
+		replicas, err := r.replicasFromConfigMap(context.Background(), "default", selector("capacity", "replicas", false))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(HaveValue(BeEquivalentTo(3)))
+	})
+
+	DescribeTable("should reject a value that is not a positive replica count",
+		func(key string) {
+			_, err := r.replicasFromConfigMap(context.Background(), "default", selector("capacity", key, false))
+			Expect(err).To(MatchError(ContainSubstring("is not a valid replica count")))
+
+			// Optional only covers a missing value, not an invalid one
+			_, err = r.replicasFromConfigMap(context.Background(), "default", selector("capacity", key, true))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("zero", "zero"),
+		Entry("negative", "negative"),
+		Entry("not an integer", "not-a-number"),
+	)
+
+	It("should reject a missing key or ConfigMap unless the reference is optional", func() {
+		_, err := r.replicasFromConfigMap(context.Background(), "default", selector("capacity", "missing", false))
+		Expect(err).To(MatchError(ContainSubstring("has no key missing")))
+		_, err = r.replicasFromConfigMap(context.Background(), "default", selector("absent", "replicas", false))
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		replicas, err := r.replicasFromConfigMap(context.Background(), "default", selector("capacity", "missing", true))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(BeNil())
+		replicas, err = r.replicasFromConfigMap(context.Background(), "default", selector("absent", "replicas", true))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas).To(BeNil())
+	})
+
+	It("should only enqueue the InferenceSchedulers that read the ConfigMap", func() {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "capacity", Namespace: "default"}}
+		Expect(r.findSchedulersForReplicasConfigMap(context.Background(), configMap)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "capacity-a", Namespace: "default"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "capacity-b", Namespace: "default"}},
+		))
+
+		configMap.Name = "unrelated"
+		Expect(r.findSchedulersForReplicasConfigMap(context.Background(), configMap)).To(BeEmpty())
+		configMap.Name, configMap.Namespace = "capacity", "elsewhere"
+		Expect(r.findSchedulersForReplicasConfigMap(context.Background(), configMap)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Rollout ordering", func() {
+	var (
+		r     *InferenceSchedulerReconciler
+		held  *appsv1.Deployment
+		first *appsv1.Deployment
+	)
+
+	deployment := func(name string, containers ...corev1.Container) *appsv1.Deployment {
+		replicas := int32(1)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers}},
+			},
+			Status: appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+		}
+	}
+
+	pod := func(workload *appsv1.Deployment, image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: workload.Name + "-" + strings.ReplaceAll(image, ":", "-"), Namespace: "default",
+				Labels: workload.Spec.Selector.MatchLabels},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "vllm", Image: image}}},
+		}
+	}
+
+	BeforeEach(func() {
+		held = deployment("test-scheduler-epp", corev1.Container{Name: "epp", Image: "epp:v1"})
+		first = deployment("test-scheduler-vllm",
+			corev1.Container{Name: "vllm", Image: "vllm:v1"},
+			corev1.Container{Name: "proxy", Image: "proxy:v1"})
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		r = &InferenceSchedulerReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(held.DeepCopy(), first.DeepCopy()).Build(),
+			Scheme: scheme,
+		}
+	})
+
+	It("should not hold the update once the first workload runs its images", func() {
+		Expect(r.holdRollout(context.Background(), held, first)).To(BeFalse())
+	})
+
+	It("should hold the update until the new image of the first workload is applied and rolled out", func() {
+		desired := first.DeepCopy()
+		desired.Spec.Template.Spec.Containers[0].Image = "vllm:v2"
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeTrue())
+
+		Expect(r.Create(context.Background(), pod(first, "vllm:v1"))).To(Succeed())
+		existing := &appsv1.Deployment{}
+		Expect(r.Get(context.Background(), client.ObjectKeyFromObject(first), existing)).To(Succeed())
+		existing.Spec.Template.Spec.Containers[0].Image = "vllm:v2"
+		Expect(r.Update(context.Background(), existing)).To(Succeed())
+		existing.Status.UpdatedReplicas = 0
+		Expect(r.Status().Update(context.Background(), existing)).To(Succeed())
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeTrue())
+
+		existing.Status.UpdatedReplicas = 1
+		Expect(r.Status().Update(context.Background(), existing)).To(Succeed())
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeFalse())
+	})
+
+	It("should not hold the update while the first workload is degraded on its current images", func() {
+		// A replica that never becomes available, e.g. pending on a GPU
+		existing := &appsv1.Deployment{}
+		Expect(r.Get(context.Background(), client.ObjectKeyFromObject(first), existing)).To(Succeed())
+		existing.Status.AvailableReplicas = 0
+		Expect(r.Status().Update(context.Background(), existing)).To(Succeed())
+		Expect(r.holdRollout(context.Background(), held, first)).To(BeFalse())
+
+		// A rollout of other template changes, e.g. a new replica, with every pod on the current images
+		Expect(r.Create(context.Background(), pod(first, "vllm:v1"))).To(Succeed())
+		existing.Status.UpdatedReplicas = 0
+		Expect(r.Status().Update(context.Background(), existing)).To(Succeed())
+		Expect(r.holdRollout(context.Background(), held, first)).To(BeFalse())
+	})
+
+	It("should match containers by name", func() {
+		desired := first.DeepCopy()
+		containers := desired.Spec.Template.Spec.Containers
+		containers[0], containers[1] = containers[1], containers[0]
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeFalse())
+
+		desired.Spec.Template.Spec.Containers = append(containers, corev1.Container{Name: "sidecar", Image: "sidecar:v1"})
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeFalse())
+	})
+
+	It("should not hold the update while the first workload is paused", func() {
+		Expect(r.Create(context.Background(), pod(first, "vllm:v1"))).To(Succeed())
+		desired := first.DeepCopy()
+		desired.Spec.Template.Spec.Containers[0].Image = "vllm:v2"
+		desired.Spec.Paused = true
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeFalse())
+
+		partition := int32(1)
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: first.ObjectMeta,
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: first.Spec.Replicas,
+				Selector: first.Spec.Selector,
+				Template: desired.Spec.Template,
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+				},
+			},
+		}
+		Expect(r.holdRollout(context.Background(), held, statefulSet)).To(BeFalse())
+	})
+
+	It("should not hold a workload that is created", func() {
+		Expect(r.Delete(context.Background(), held.DeepCopy())).To(Succeed())
+		desired := first.DeepCopy()
+		desired.Spec.Template.Spec.Containers[0].Image = "vllm:v2"
+		Expect(r.holdRollout(context.Background(), held, desired)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Reconcile with a fake client", func() {
+	var (
+		ctx      context.Context
+		key      types.NamespacedName
+		recorder *record.FakeRecorder
+		r        *InferenceSchedulerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "test-scheduler", Namespace: "default"}
+		infScheduler := &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              key.Name,
+				Namespace:         key.Namespace,
+				Generation:        1,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{
+					ModelName:         "Qwen/Qwen2.5-0.5B-Instruct",
+					HFTokenSecretName: "hf-token",
+					Replicas:          1,
+				},
+				EndpointPicker: llmv1alpha1.EndpointPickerSpec{Replicas: 1},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+		// Serve the CRD-backed kinds as unstructured objects
+		for _, gvk := range append(unstructuredChildGVKs, schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GatewayClass"}) {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		}
+
+		gatewayClass := &unstructured.Unstructured{}
+		gatewayClass.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "GatewayClass"})
+		gatewayClass.SetName("kgateway")
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				infScheduler,
+				gatewayClass,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "hf-token", Namespace: "default"},
+					Data:       map[string][]byte{"token": []byte("hf_test")},
+				},
+			).
+			WithStatusSubresource(&llmv1alpha1.InferenceScheduler{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				// Nothing runs the workloads or the gateway, so report them as ready and accepted
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := c.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					switch obj := obj.(type) {
+					case *appsv1.Deployment:
+						obj.Status.ReadyReplicas = *obj.Spec.Replicas
+					case *unstructured.Unstructured:
+						if obj.GetKind() == "InferencePool" {
+							parents := []interface{}{map[string]interface{}{
+								"conditions": []interface{}{map[string]interface{}{"type": "Accepted", "status": "True"}},
+							}}
+							Expect(unstructured.SetNestedSlice(obj.Object, parents, "status", "parents")).To(Succeed())
+						}
+					}
+					return nil
+				},
+			}).
+			Build()
+		recorder = record.NewFakeRecorder(100)
+		r = &InferenceSchedulerReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+	})
+
+	It("should record the transitions of a successful reconcile", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal("Ready"))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "Ready")).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, "Degraded")).To(BeTrue())
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ContainElements(
+			"Normal PrerequisitesValidated Gateway API, GIE, and GatewayClass are present",
+			"Normal Created Created Deployment test-scheduler-vllm",
+			"Normal ModelServerReady All model server pods are running",
+			"Normal EPPReady EPP is running",
+			"Normal Created Created InferencePool test-scheduler-pool",
+			"Normal GatewayReady Gateway and HTTPRoute created successfully",
+			"Normal InferencePoolReady InferencePool is accepted by the Gateway",
+		))
+
+		// Conditions that keep their status do not record the transition again
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should skip the reconcile while the namespace is terminating or once it is gone", func() {
+		namespace := &corev1.Namespace{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "default"}, namespace)).To(Succeed())
+		namespace.Status.Phase = corev1.NamespaceTerminating
+		Expect(r.Status().Update(ctx, namespace)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal("Terminating"))
+		degraded := meta.FindStatusCondition(updated.Status.Conditions, "Degraded")
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("NamespaceTerminating"))
+
+		// The namespace is deleted before the InferenceScheduler is
+		Expect(r.Delete(ctx, namespace)).To(Succeed())
+		result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+	})
+
+	It("should replace the default finalizer with the configured one once", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Finalizers = []string{DefaultFinalizerName}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		updates := 0
+		r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if _, ok := obj.(*llmv1alpha1.InferenceScheduler); ok {
+					updates++
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		})
+		r.FinalizerName = "example.com/inference-scheduler"
+
+		for range 2 {
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			Expect(infScheduler.Finalizers).To(Equal([]string{"example.com/inference-scheduler"}))
+		}
+		Expect(updates).To(Equal(1))
+	})
+
+	It("should report the ready model server replicas scaled by the autoscaler", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ModelServer.Autoscaling = &llmv1alpha1.AutoscalingSpec{MaxReplicas: 4}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		// The HorizontalPodAutoscaler has scaled the model server past spec.modelServer.replicas
+		replicas := int32(3)
+		Expect(r.Create(ctx, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-scheduler-vllm", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		})).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(infScheduler.Status.Conditions, "ModelServerReady")).To(BeTrue())
+		Expect(infScheduler.Status.ModelServerReplicas).To(Equal(int32(3)))
+	})
+
+	It("should report the generation of the last reconciled spec", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(1)))
+
+		// The fake client does not maintain the generation, so bump it as the API server would
+		updated.Spec.ModelServer.Replicas = 2
+		updated.Generation = 2
+		Expect(r.Update(ctx, updated)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should record the time to the first Ready phase", func() {
+		observations := func() uint64 {
+			metric := &dto.Metric{}
+			Expect(timeToReady.Write(metric)).To(Succeed())
+			return metric.GetHistogram().GetSampleCount()
+		}
+		observed := observations()
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.TimeToReady).NotTo(BeNil())
+		Expect(updated.Status.TimeToReady.Duration).To(BeNumerically("~", 10*time.Minute, time.Minute))
+		first := *updated.Status.TimeToReady
+		Expect(observations()).To(Equal(observed + 1))
+
+		// Later reconciles leave it alone
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(*updated.Status.TimeToReady).To(Equal(first))
+		Expect(observations()).To(Equal(observed + 1))
+	})
+
+	It("should not adopt a child controlled by another controller", func() {
+		controller, replicas := true, int32(1)
+		foreign := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-scheduler-vllm",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "example.com/v1",
+					Kind:       "Application",
+					Name:       "other",
+					UID:        "other-uid",
+					Controller: &controller,
+				}},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+		Expect(r.Create(ctx, foreign)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(60 * time.Second))
+
+		updated := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		degraded := meta.FindStatusCondition(updated.Status.Conditions, "Degraded")
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("OwnershipConflict"))
+		Expect(degraded.Message).To(ContainSubstring("default/test-scheduler-vllm is controlled by Application other"))
+
+		deployment := &appsv1.Deployment{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), deployment)).To(Succeed())
+		Expect(deployment.OwnerReferences).To(Equal(foreign.OwnerReferences))
+
+		// Once the conflicting Deployment is gone the operator creates its own and clears the condition
+		Expect(r.Delete(ctx, deployment)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, updated)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, "Degraded")).To(BeTrue())
+	})
+
+	It("should attach to an existing Gateway without creating or owning it", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Gateway.ExistingGatewayRef = &llmv1alpha1.GatewayReference{Name: "shared", Namespace: "platform"}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		// The referenced Gateway does not exist yet
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(60 * time.Second))
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "GatewayReady").Reason).To(Equal("GatewayNotFound"))
+
+		shared := &unstructured.Unstructured{}
+		shared.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"})
+		shared.SetName("shared")
+		shared.SetNamespace("platform")
+		Expect(r.Create(ctx, shared)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Phase).To(Equal("Ready"))
+
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(shared.GroupVersionKind())
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-gateway", Namespace: "default"}, gateway))).To(BeTrue())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(shared), gateway)).To(Succeed())
+		Expect(gateway.GetOwnerReferences()).To(BeEmpty())
+
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-route", Namespace: "default"}, route)).To(Succeed())
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		Expect(parentRefs).To(ConsistOf(map[string]interface{}{"name": "shared", "namespace": "platform"}))
+	})
+
+	It("should not apply any child with settings for a created Gateway and an existing Gateway", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Gateway.ClassName = "kgateway"
+		infScheduler.Spec.Gateway.ExistingGatewayRef = &llmv1alpha1.GatewayReference{Name: "shared", Namespace: "platform"}
+		infScheduler.Spec.Gateway.TLS = &llmv1alpha1.GatewayTLSSpec{CertificateSecretName: "inference-tls"}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "GatewayConfigValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("ConflictingGatewaySettings"))
+		// The defaulted className is not reported
+		Expect(condition.Message).NotTo(ContainSubstring("className"))
+	})
+
+	It("should validate the spec before applying the maintenance routes", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.MaintenanceMode = true
+		infScheduler.Spec.EndpointPicker.GRPCPort = 9003
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		gatewayKey := types.NamespacedName{Name: "test-scheduler-gateway", Namespace: "default"}
+		routeKey := types.NamespacedName{Name: "test-scheduler-route", Namespace: "default"}
+		gatewayGVK := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+
+		// Conflicting EPP ports
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(gatewayGVK)
+		Expect(errors.IsNotFound(r.Get(ctx, gatewayKey, gateway))).To(BeTrue())
+		Expect(errors.IsNotFound(r.Get(ctx, routeKey, route))).To(BeTrue())
+
+		// A Gateway of the same name, not controlled by the InferenceScheduler, serves another protocol
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.EndpointPicker.GRPCPort = 0
+		infScheduler.Spec.Gateway.ListenerPort = 80
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(gatewayGVK)
+		existing.SetName(gatewayKey.Name)
+		existing.SetNamespace(gatewayKey.Namespace)
+		listeners := []interface{}{map[string]interface{}{"name": "https", "protocol": "HTTPS", "port": int64(80)}}
+		Expect(unstructured.SetNestedSlice(existing.Object, listeners, "spec", "listeners")).To(Succeed())
+		Expect(r.Create(ctx, existing)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(60 * time.Second))
+		Expect(r.Get(ctx, gatewayKey, gateway)).To(Succeed())
+		Expect(gateway.Object["spec"]).To(Equal(existing.Object["spec"]))
+		Expect(errors.IsNotFound(r.Get(ctx, routeKey, route))).To(BeTrue())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "GatewayReady").Reason).To(Equal("ListenerConflict"))
+	})
+
+	It("should revert drift of its own fields and keep the fields set by others", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ModelServer.DeploymentAnnotations = map[string]string{"team": "inference"}
+		infScheduler.Spec.ModelServer.NodeSelector = map[string]string{"gpu": "a100"}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Someone scales the Deployment by hand and another tool annotates it
+		deploymentKey := types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}
+		deployment := &appsv1.Deployment{}
+		Expect(r.Get(ctx, deploymentKey, deployment)).To(Succeed())
+		replicas := int32(5)
+		deployment.Spec.Replicas = &replicas
+		deployment.Annotations["example.com/owner"] = "platform"
+		deployment.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+		Expect(r.Update(ctx, deployment)).To(Succeed())
+
+		// The operator stops setting a field
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ModelServer.NodeSelector = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, deploymentKey, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(1)))
+		Expect(deployment.Annotations).To(HaveKeyWithValue("example.com/owner", "platform"))
+		Expect(deployment.Annotations).To(HaveKeyWithValue("team", "inference"))
+		Expect(deployment.Spec.Template.Spec.DNSPolicy).To(Equal(corev1.DNSClusterFirst))
+		Expect(deployment.Spec.Template.Spec.NodeSelector).To(BeEmpty())
+	})
+
+	It("should replace the generated EPP ConfigMap with a referenced one", func() {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		configMapKey := types.NamespacedName{Name: "test-scheduler-epp-config", Namespace: "default"}
+		Expect(r.Get(ctx, configMapKey, &corev1.ConfigMap{})).To(Succeed())
+
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.EndpointPicker.PluginConfigMapRef = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "custom-epp-config"},
+			Key:                  "plugins.yaml",
+		}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(errors.IsNotFound(r.Get(ctx, configMapKey, &corev1.ConfigMap{}))).To(BeTrue())
+		deployment := &appsv1.Deployment{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-epp", Namespace: "default"}, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("custom-epp-config"))
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "PluginConfigValid").Reason).To(Equal("ConfigMapOverride"))
+	})
+
+	It("should report the Gateway address once it is assigned", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Gateway.ListenerPort = 80
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Phase).To(Equal("Ready"))
+		Expect(infScheduler.Status.GatewayAddress).To(BeEmpty())
+		Expect(infScheduler.Status.EndpointURL).To(BeEmpty())
+
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"})
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-gateway", Namespace: "default"}, gateway)).To(Succeed())
+		addresses := []interface{}{map[string]interface{}{"type": "IPAddress", "value": "10.0.0.1"}}
+		Expect(unstructured.SetNestedSlice(gateway.Object, addresses, "status", "addresses")).To(Succeed())
+		Expect(r.Update(ctx, gateway)).To(Succeed())
+
+		result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.GatewayAddress).To(Equal("10.0.0.1"))
+		Expect(infScheduler.Status.EndpointURL).To(Equal("http://10.0.0.1:80/v1"))
+	})
+
+	It("should record the plan without applying it in dry-run mode", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Annotations = map[string]string{dryRunAnnotation: "true"}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		deploymentKey := types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}
+		Expect(errors.IsNotFound(r.Get(ctx, deploymentKey, &appsv1.Deployment{}))).To(BeTrue())
+		gateway := &unstructured.Unstructured{}
+		gateway.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"})
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-gateway", Namespace: "default"}, gateway))).To(BeTrue())
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Phase).To(Equal("DryRun"))
+		Expect(infScheduler.Status.Plan).To(ContainElements(
+			"create Deployment test-scheduler-vllm",
+			"create Service test-scheduler-vllm",
+			"create Gateway test-scheduler-gateway",
+			"create HTTPRoute test-scheduler-route",
+		))
+		Expect(meta.IsStatusConditionTrue(infScheduler.Status.Conditions, "DryRun")).To(BeTrue())
+
+		// Apply for real, then plan a change
+		delete(infScheduler.Annotations, dryRunAnnotation)
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Plan).To(BeEmpty())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "DryRun")).To(BeNil())
+
+		infScheduler.SetAnnotations(map[string]string{dryRunAnnotation: "true"})
+		infScheduler.Spec.ModelServer.Replicas = 3
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Plan).To(ConsistOf("update Deployment test-scheduler-vllm: spec.replicas"))
+		deployment := &appsv1.Deployment{}
+		Expect(r.Get(ctx, deploymentKey, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(1)))
+	})
+
+	It("should not plan a spec that fails validation", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Annotations = map[string]string{dryRunAnnotation: "true"}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Plan).NotTo(BeEmpty())
+
+		infScheduler.Spec.ModelServer.ModelName = "Qwen/Qwen2.5 0.5B"
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Plan).To(BeEmpty())
+		Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "ModelNameValid")).To(BeTrue())
+	})
+
+	It("should delete children that the spec no longer asks for", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Gateway.HealthCheckRoute = true
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		healthRouteKey := types.NamespacedName{Name: "test-scheduler-health-route", Namespace: "default"}
+		gatewayKey := types.NamespacedName{Name: "test-scheduler-gateway", Namespace: "default"}
+		child := func(kind string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: kind})
+			return obj
+		}
+		Expect(r.Get(ctx, healthRouteKey, child("HTTPRoute"))).To(Succeed())
+
+		// Disabling a feature deletes its resource, and only that one
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Gateway.HealthCheckRoute = false
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(r.Get(ctx, healthRouteKey, child("HTTPRoute")))).To(BeTrue())
+		Expect(r.Get(ctx, gatewayKey, child("Gateway"))).To(Succeed())
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{})).To(Succeed())
+
+		// Switching to an existing Gateway deletes the one created before, but not the shared one
+		shared := child("Gateway")
+		shared.SetName("shared-gateway")
+		shared.SetNamespace("default")
+		Expect(r.Create(ctx, shared)).To(Succeed())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Gateway.ExistingGatewayRef = &llmv1alpha1.GatewayReference{Name: "shared-gateway"}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(r.Get(ctx, gatewayKey, child("Gateway")))).To(BeTrue())
+		Expect(r.Get(ctx, types.NamespacedName{Name: "shared-gateway", Namespace: "default"}, child("Gateway"))).To(Succeed())
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ContainElements(
+			"Normal Deleted Deleted HTTPRoute test-scheduler-health-route",
+			"Normal Deleted Deleted Gateway test-scheduler-gateway",
+		))
+	})
+
+	It("should poll a model server that is not ready less often", func() {
+		// The model server never becomes ready
+		r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil {
+					return err
+				}
+				if deployment, ok := obj.(*appsv1.Deployment); ok && deployment.Name == "test-scheduler-vllm" {
+					deployment.Status.ReadyReplicas = 0
+				}
+				return nil
+			},
+		})
+
+		var intervals []time.Duration
+		for range 3 {
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			intervals = append(intervals, result.RequeueAfter)
+		}
+		Expect(intervals).To(Equal([]time.Duration{30 * time.Second, time.Minute, 2 * time.Minute}))
+
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Phase).NotTo(Equal("Ready"))
+		// The prerequisites are validated but nothing is running yet
+		degraded := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded")
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("PartiallyReady"))
+		Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "Ready")).To(BeTrue())
+	})
+
+	It("should deploy extra model servers and remove them again", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ExtraModelServers = []llmv1alpha1.ExtraModelServerSpec{
+			{Name: "variant-b", ModelName: "Qwen/Qwen2.5-1.5B-Instruct"},
+		}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Phase).To(Equal("Ready"))
+
+		variantKey := types.NamespacedName{Name: "test-scheduler-variant-b-vllm", Namespace: "default"}
+		Expect(r.Get(ctx, variantKey, &appsv1.Deployment{})).To(Succeed())
+		Expect(r.Get(ctx, variantKey, &corev1.Service{})).To(Succeed())
+
+		infScheduler.Spec.ExtraModelServers = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(r.Get(ctx, variantKey, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(errors.IsNotFound(r.Get(ctx, variantKey, &corev1.Service{}))).To(BeTrue())
+	})
+
+	It("should remove the extra model servers condition once the invalid extra model servers are removed", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ExtraModelServers = []llmv1alpha1.ExtraModelServerSpec{
+			{Name: "variant-b", ModelName: infScheduler.Spec.ModelServer.ModelName},
+		}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "ExtraModelServersValid")).To(BeTrue())
+
+		infScheduler.Spec.ExtraModelServers = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "ExtraModelServersValid")).To(BeNil())
+	})
+
+	It("should not deploy a model server with an invalid model name", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ModelServer.ModelName = "Qwen/Qwen2.5 0.5B"
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelNameValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("InvalidModelName"))
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ContainElement(HavePrefix("Warning InvalidModelName modelServer: model name")))
+	})
+
+	It("should remove the model cache condition once the invalid model cache is removed", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ModelServer.ModelCache = &llmv1alpha1.ModelCacheSpec{}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "ModelCacheValid")).To(BeTrue())
+
+		infScheduler.Spec.ModelServer.ModelCache = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelCacheValid")).To(BeNil())
+	})
+
+	It("should remove the EPP ports condition once the conflicting ports are removed", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		port := int32(9090)
+		infScheduler.Spec.EndpointPicker.HealthPort = &port
+		infScheduler.Spec.EndpointPicker.MetricsPort = &port
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "EPPPortsValid")).To(BeTrue())
+
+		infScheduler.Spec.EndpointPicker.HealthPort = nil
+		infScheduler.Spec.EndpointPicker.MetricsPort = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "EPPPortsValid")).To(BeNil())
+	})
+
+	It("should remove the model server shutdown condition once the preStop sleep is removed", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		sleep := int32(60)
+		infScheduler.Spec.ModelServer.PreStopSleepSeconds = &sleep
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerShutdownValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("PreStopTooLong"))
+
+		infScheduler.Spec.ModelServer.PreStopSleepSeconds = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerShutdownValid")).To(BeNil())
+	})
+
+	It("should remove the PodDisruptionBudget condition once the invalid budgets are removed", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		minAvailable := intstr.FromInt32(1)
+		maxUnavailable := intstr.FromInt32(1)
+		infScheduler.Spec.EndpointPicker.PodDisruptionBudget = &llmv1alpha1.PodDisruptionBudgetSpec{
+			MinAvailable:   &minAvailable,
+			MaxUnavailable: &maxUnavailable,
+		}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "PodDisruptionBudgetValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("InvalidBudget"))
+
+		infScheduler.Spec.EndpointPicker.PodDisruptionBudget = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "PodDisruptionBudgetValid")).To(BeNil())
+	})
+
+	It("should not apply any child with an invalid EPP node port", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		nodePort := int32(30902)
+		infScheduler.Spec.EndpointPicker.NodePort = &llmv1alpha1.EPPNodePortSpec{GRPC: &nodePort, Health: &nodePort}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-epp", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "EPPNodePortValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("InvalidNodePort"))
+
+		// Removing the node ports removes the condition along with them
+		infScheduler.Spec.EndpointPicker.NodePort = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "EPPNodePortValid")).To(BeNil())
+	})
+
+	It("should not apply any child with a node port on a ClusterIP model server Service", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		nodePort := int32(30800)
+		infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{NodePort: &nodePort}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(errors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, &corev1.Service{}))).To(BeTrue())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerServiceValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("InvalidNodePort"))
+
+		// Removing the node port removes the condition along with it
+		infScheduler.Spec.ModelServer.Service.NodePort = nil
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerServiceValid")).To(BeNil())
+	})
+
+	It("should deploy a public model without a token secret", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.ModelServer.HFTokenSecretName = ""
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.Phase).To(Equal("Ready"))
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "HFTokenValid")
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("NoToken"))
+
+		deployment := &appsv1.Deployment{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-vllm", Namespace: "default"}, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).NotTo(ContainElement(HaveField("Name", "HF_TOKEN")))
+	})
+
+	It("should report the enabled EPP plugins and the config hash", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer = &llmv1alpha1.ScorerPlugin{Enabled: true}
+		infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer = &llmv1alpha1.ScorerPlugin{Enabled: true}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(infScheduler.Status.ActivePlugins).To(Equal([]string{"load-aware-scorer=1.0", "prefix-cache-scorer=2.0"}))
+		configMap := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-epp-config", Namespace: "default"}, configMap)).To(Succeed())
+		Expect(infScheduler.Status.PluginConfigHash).To(Equal(configMap.Annotations[configHashAnnotation]))
+	})
+
+	It("should report a crash looping EPP as Degraded with its termination message", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		eppSelector := r.buildEPPDeployment(infScheduler).Spec.Selector.MatchLabels
+
+		// Keep the EPP Deployment unready while its pod crash loops
+		eppReady := false
+		r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil {
+					return err
+				}
+				if deployment, ok := obj.(*appsv1.Deployment); ok && deployment.Name == "test-scheduler-epp" && !eppReady {
+					deployment.Status.ReadyReplicas = 0
+				}
+				return nil
+			},
+		})
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-scheduler-epp-abc12", Namespace: "default", Labels: eppSelector},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "epp",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 2,
+						Reason:   "Error",
+						Message:  "flag provided but not defined: -pool-name\n",
+					}},
+				}},
+			},
+		}
+		Expect(r.Create(ctx, pod)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded")
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("EPPCrashLooping"))
+		Expect(condition.Message).To(Equal("container epp of pod test-scheduler-epp-abc12 is in CrashLoopBackOff; " +
+			"last termination: flag provided but not defined: -pool-name"))
+
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ContainElement(HavePrefix("Warning EPPCrashLooping container epp")))
+
+		// Fixed pods clear the condition
+		Expect(r.Delete(ctx, pod)).To(Succeed())
+		eppReady = true
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "Degraded")).To(BeTrue())
+	})
+
+	Context("When the model server is not ready", func() {
+		var modelServerReady bool
+
+		BeforeEach(func() {
+			modelServerReady = false
+			r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := c.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if deployment, ok := obj.(*appsv1.Deployment); ok && deployment.Name == "test-scheduler-vllm" && !modelServerReady {
+						deployment.Status.ReadyReplicas = 0
+					}
+					return nil
+				},
+			})
+		})
+
+		routingChild := func(kind, name string) error {
+			group := "gateway.networking.k8s.io"
+			if kind == "InferencePool" {
+				group = "inference.networking.k8s.io"
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(schema.GroupVersionKind{Group: group, Version: "v1", Kind: kind})
+			return r.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, obj)
+		}
+
+		It("should defer the routing until the model server is ready", func() {
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+			Expect(errors.IsNotFound(routingChild("InferencePool", "test-scheduler-pool"))).To(BeTrue())
+			Expect(errors.IsNotFound(routingChild("Gateway", "test-scheduler-gateway"))).To(BeTrue())
+
+			modelServerReady = true
+			_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routingChild("InferencePool", "test-scheduler-pool")).To(Succeed())
+			Expect(routingChild("Gateway", "test-scheduler-gateway")).To(Succeed())
+
+			// Routes that already exist are reported as not serving once the model server is lost
+			modelServerReady = false
+			_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			infScheduler := &llmv1alpha1.InferenceScheduler{}
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "GatewayReady")
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("BackendNotReady"))
+			Expect(infScheduler.Status.GatewayReady).To(BeFalse())
+		})
+
+		It("should report a model server that misses its readiness deadline as Degraded", func() {
+			infScheduler := &llmv1alpha1.InferenceScheduler{}
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			infScheduler.Spec.ModelServer.ReadinessDeadline = &metav1.Duration{Duration: 15 * time.Minute}
+			Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+			// Within the deadline the model server is polled as usual
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded").Reason).NotTo(Equal("ReadinessDeadlineExceeded"))
+
+			// The model server went not ready longer ago than the deadline
+			condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerReady")
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			condition.LastTransitionTime = metav1.NewTime(time.Now().Add(-20 * time.Minute))
+			Expect(r.Status().Update(ctx, infScheduler)).To(Succeed())
+
+			result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			condition = meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ReadinessDeadlineExceeded"))
+			Expect(condition.Message).To(ContainSubstring("15m0s"))
+
+			// Becoming ready clears it
+			modelServerReady = true
+			_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			Expect(meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded").Reason).NotTo(Equal("ReadinessDeadlineExceeded"))
+		})
+
+		It("should keep applying the routing with Immediate routing once the readiness deadline has passed", func() {
+			infScheduler := &llmv1alpha1.InferenceScheduler{}
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			infScheduler.Spec.RoutingOrder = "Immediate"
+			infScheduler.Spec.ModelServer.ReadinessDeadline = &metav1.Duration{Duration: 15 * time.Minute}
+			Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			// The model server went not ready longer ago than the deadline, and the EPP is scaled meanwhile
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "ModelServerReady")
+			condition.LastTransitionTime = metav1.NewTime(time.Now().Add(-20 * time.Minute))
+			Expect(r.Status().Update(ctx, infScheduler)).To(Succeed())
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			infScheduler.Spec.EndpointPicker.Replicas = 2
+			Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			condition = meta.FindStatusCondition(infScheduler.Status.Conditions, "Degraded")
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ReadinessDeadlineExceeded"))
+
+			epp := &appsv1.Deployment{}
+			Expect(r.Get(ctx, types.NamespacedName{Name: "test-scheduler-epp", Namespace: "default"}, epp)).To(Succeed())
+			Expect(*epp.Spec.Replicas).To(Equal(int32(2)))
+			Expect(routingChild("InferencePool", "test-scheduler-pool")).To(Succeed())
+			Expect(routingChild("HTTPRoute", "test-scheduler-route")).To(Succeed())
+		})
+
+		It("should create the routing right away with Immediate routing", func() {
+			infScheduler := &llmv1alpha1.InferenceScheduler{}
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			infScheduler.Spec.RoutingOrder = "Immediate"
+			Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+			Expect(routingChild("InferencePool", "test-scheduler-pool")).To(Succeed())
+			Expect(routingChild("Gateway", "test-scheduler-gateway")).To(Succeed())
+			Expect(routingChild("HTTPRoute", "test-scheduler-route")).To(Succeed())
+
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			Expect(infScheduler.Status.Phase).To(Equal("Deploying"))
+			Expect(meta.IsStatusConditionFalse(infScheduler.Status.Conditions, "ModelServerReady")).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(infScheduler.Status.Conditions, "EPPReady")).To(BeTrue())
+			condition := meta.FindStatusCondition(infScheduler.Status.Conditions, "GatewayReady")
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("BackendNotReady"))
+
+			modelServerReady = true
+			_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+			Expect(infScheduler.Status.Phase).To(Equal("Ready"))
+			Expect(meta.IsStatusConditionTrue(infScheduler.Status.Conditions, "GatewayReady")).To(BeTrue())
+		})
+	})
+
+	It("should create the Grafana dashboard ConfigMap and remove it once disabled", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{Dashboard: true}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		dashboardKey := types.NamespacedName{Name: "test-scheduler-dashboard", Namespace: "default"}
+		configMap := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, dashboardKey, configMap)).To(Succeed())
+		Expect(configMap.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+		Expect(configMap.Data["default-test-scheduler.json"]).To(ContainSubstring("test-scheduler-vllm"))
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Monitoring.Dashboard = false
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(r.Get(ctx, dashboardKey, &corev1.ConfigMap{}))).To(BeTrue())
+	})
+
+	It("should delete children with a non-controller owner reference that the spec no longer asks for", func() {
+		infScheduler := &llmv1alpha1.InferenceScheduler{}
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		controller := false
+		infScheduler.Spec.OwnerReferences = []llmv1alpha1.OwnerReferencePolicy{{Kind: "ConfigMap", Controller: &controller}}
+		infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{Dashboard: true}
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		dashboardKey := types.NamespacedName{Name: "test-scheduler-dashboard", Namespace: "default"}
+		configMap := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, dashboardKey, configMap)).To(Succeed())
+		Expect(metav1.GetControllerOf(configMap)).To(BeNil())
+		Expect(configMap.OwnerReferences).To(HaveLen(1))
+
+		Expect(r.Get(ctx, key, infScheduler)).To(Succeed())
+		infScheduler.Spec.Monitoring.Dashboard = false
+		Expect(r.Update(ctx, infScheduler)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(r.Get(ctx, dashboardKey, &corev1.ConfigMap{}))).To(BeTrue())
+	})
+})
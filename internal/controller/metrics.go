@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// managedSchedulers reports the number of InferenceSchedulers handled by this operator, by phase
+	managedSchedulers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inferencescheduler_managed",
+			Help: "Number of InferenceSchedulers managed by the operator, partitioned by phase",
+		},
+		[]string{"phase"},
+	)
+
+	// schedulerReady reports whether each InferenceScheduler is Ready. The labels avoid "namespace",
+	// which Prometheus sets to the operator's namespace when scraping
+	schedulerReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "inferencescheduler_ready",
+			Help: "Whether the InferenceScheduler is in the Ready phase (1) or not (0)",
+		},
+		[]string{"inferencescheduler_namespace", "inferencescheduler"},
+	)
+
+	// timeToReady observes how long InferenceSchedulers take from creation to their first Ready phase
+	timeToReady = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name: "inferencescheduler_time_to_ready_seconds",
+			Help: "Time from the creation of an InferenceScheduler to the first time it is Ready",
+			// Model downloads and loading take minutes, large models up to an hour
+			Buckets: []float64{30, 60, 120, 300, 600, 900, 1800, 3600},
+		},
+	)
+
+	// phases tracks the last observed phase of every InferenceScheduler
+	phases = &phaseTracker{phases: map[types.NamespacedName]string{}}
+)
+
+func init() {
+	// Register with the controller-runtime registry so the gauge is served on the manager's metrics endpoint
+	metrics.Registry.MustRegister(managedSchedulers, schedulerReady, timeToReady)
+}
+
+// phaseTracker keeps the managedSchedulers gauge consistent with the set of known InferenceSchedulers
+type phaseTracker struct {
+	mu     sync.Mutex
+	phases map[types.NamespacedName]string
+}
+
+// record stores the phase of an InferenceScheduler and refreshes the gauge
+func (t *phaseTracker) record(key types.NamespacedName, phase string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.phases[key] = phase
+	t.refresh()
+
+	ready := 0.0
+	if phase == "Ready" {
+		ready = 1
+	}
+	schedulerReady.WithLabelValues(key.Namespace, key.Name).Set(ready)
+}
+
+// forget removes a deleted InferenceScheduler and refreshes the gauge
+func (t *phaseTracker) forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.phases, key)
+	t.refresh()
+	schedulerReady.DeleteLabelValues(key.Namespace, key.Name)
+}
+
+// refresh recomputes the gauge from the tracked phases. Callers must hold t.mu.
+func (t *phaseTracker) refresh() {
+	counts := map[string]float64{}
+	for _, phase := range t.phases {
+		counts[phase]++
+	}
+
+	managedSchedulers.Reset()
+	for phase, count := range counts {
+		managedSchedulers.WithLabelValues(phase).Set(count)
+	}
+}
+
+// InformerSyncCheck returns a readiness check that fails until the informer caches used by the
+// reconciler have synced
+func InformerSyncCheck(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		if !c.WaitForCacheSync(req.Context()) {
+			return errors.New("informer caches have not synced")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// syncedCache is a cache whose informers have or have not synced
+type syncedCache struct {
+	cache.Cache
+	synced bool
+}
+
+func (c *syncedCache) WaitForCacheSync(context.Context) bool {
+	return c.synced
+}
+
+var _ = Describe("Operator metrics", func() {
+	// managedByPhase returns the inferencescheduler_managed gauge by phase label
+	managedByPhase := func() map[string]float64 {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		values := map[string]float64{}
+		for _, family := range families {
+			if family.GetName() != "inferencescheduler_managed" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "phase" {
+						values[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return values
+	}
+
+	It("should count the managed InferenceSchedulers by their current phase", func() {
+		tracker := &phaseTracker{phases: map[types.NamespacedName]string{}}
+		a := types.NamespacedName{Namespace: "team-a", Name: "llama"}
+		b := types.NamespacedName{Namespace: "team-b", Name: "qwen"}
+
+		tracker.record(a, "Initializing")
+		tracker.record(b, "Initializing")
+		Expect(managedByPhase()).To(Equal(map[string]float64{"Initializing": 2}))
+
+		// A phase that no InferenceScheduler is in any more is dropped rather than reported as 0
+		tracker.record(a, "Ready")
+		tracker.record(b, "Ready")
+		Expect(managedByPhase()).To(Equal(map[string]float64{"Ready": 2}))
+
+		tracker.forget(a)
+		Expect(managedByPhase()).To(Equal(map[string]float64{"Ready": 1}))
+	})
+
+	It("should report not ready until the informer caches have synced", func() {
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		Expect(InformerSyncCheck(&syncedCache{})(req)).To(MatchError(ContainSubstring("have not synced")))
+		Expect(InformerSyncCheck(&syncedCache{synced: true})(req)).To(Succeed())
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// childKey identifies a child of an InferenceScheduler, which are all in its namespace
+type childKey struct {
+	schema.GroupKind
+	Name string
+}
+
+// childLists returns an empty list for every kind the operator creates, matching the kinds
+// watched in SetupWithManager
+func childLists() []client.ObjectList {
+	lists := []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&appsv1.StatefulSetList{},
+		&autoscalingv2.HorizontalPodAutoscalerList{},
+		&policyv1.PodDisruptionBudgetList{},
+		&corev1.ServiceList{},
+		&corev1.ServiceAccountList{},
+		&corev1.ConfigMapList{},
+		&corev1.PersistentVolumeClaimList{},
+		&rbacv1.RoleList{},
+		&rbacv1.RoleBindingList{},
+	}
+	for _, gvk := range unstructuredChildGVKs {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		lists = append(lists, list)
+	}
+	return lists
+}
+
+// findOrphans returns the children owned by the InferenceScheduler that the current spec no
+// longer asks for, e.g. the Gateway after switching to existingGatewayRef or the health check
+// HTTPRoute once it is disabled
+func (r *InferenceSchedulerReconciler) findOrphans(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) ([]client.Object, error) {
+	desired := map[childKey]bool{}
+	for _, obj := range BuildChildObjects(infScheduler) {
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		desired[childKey{GroupKind: gvk.GroupKind(), Name: obj.GetName()}] = true
+	}
+	// A referenced Gateway is never deleted, even one this InferenceScheduler created earlier
+	if infScheduler.Spec.Gateway.ExistingGatewayRef != nil {
+		if key := gatewayKey(infScheduler); key.Namespace == infScheduler.Namespace {
+			desired[childKey{GroupKind: schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "Gateway"}, Name: key.Name}] = true
+		}
+	}
+
+	var orphans []client.Object
+	for _, list := range childLists() {
+		if err := r.List(ctx, list, client.InNamespace(infScheduler.Namespace)); err != nil {
+			// Optional kinds such as the Prometheus Operator ones may not be installed
+			if isKindMissing(err) {
+				continue
+			}
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !isOwnedBy(obj, infScheduler) {
+				continue
+			}
+			gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+			if err != nil {
+				return nil, err
+			}
+			if !desired[childKey{GroupKind: gvk.GroupKind(), Name: obj.GetName()}] {
+				orphans = append(orphans, obj)
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// deleteOrphans deletes the children the current spec no longer asks for. Owner references only
+// clean up once the InferenceScheduler itself is deleted, so without this a disabled feature
+// would leave its resources behind
+func (r *InferenceSchedulerReconciler) deleteOrphans(ctx context.Context, infScheduler *llmv1alpha1.InferenceScheduler) error {
+	logger := log.FromContext(ctx)
+
+	orphans, err := r.findOrphans(ctx, infScheduler)
+	if err != nil {
+		return err
+	}
+	for _, obj := range orphans {
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return err
+		}
+		logger.Info("Deleting child no longer in the spec", "kind", gvk.Kind, "name", obj.GetName())
+		if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		r.recordEvent(infScheduler, corev1.EventTypeNormal, "Deleted", fmt.Sprintf("Deleted %s %s", gvk.Kind, obj.GetName()))
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// BuildChildObjects returns the objects the operator creates for an InferenceScheduler, in the
+// order Reconcile applies them. It does not talk to a cluster, and no owner references are set
+func BuildChildObjects(infScheduler *llmv1alpha1.InferenceScheduler) []client.Object {
+	r := &InferenceSchedulerReconciler{}
+
+	var objects []client.Object
+	if cache := infScheduler.Spec.ModelServer.ModelCache; cache != nil && cache.Claim != nil {
+		objects = append(objects, r.buildModelCachePVC(infScheduler))
+	}
+	if infScheduler.Spec.ModelServer.LogFormat == logFormatJSON {
+		objects = append(objects, r.buildModelServerLoggingConfigMap(infScheduler))
+	}
+	objects = append(objects, r.buildModelServerWorkload(infScheduler))
+	if infScheduler.Spec.ModelServer.Autoscaling != nil {
+		objects = append(objects, r.buildModelServerHPA(infScheduler))
+	}
+	if pdb := r.buildModelServerPDB(infScheduler); pdb != nil {
+		objects = append(objects, pdb)
+	}
+	objects = append(objects, r.buildModelServerService(infScheduler))
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		objects = append(objects, r.buildModelServerHeadlessService(infScheduler))
+	}
+	for _, extra := range infScheduler.Spec.ExtraModelServers {
+		objects = append(objects, r.buildExtraModelServerObjects(infScheduler, extra)...)
+	}
+	objects = append(objects,
+		r.buildEPPServiceAccount(infScheduler),
+		r.buildEPPRole(infScheduler),
+		r.buildEPPRoleBinding(infScheduler),
+	)
+	if configMap := r.buildEPPConfigMap(infScheduler); configMap != nil {
+		objects = append(objects, configMap)
+	}
+	objects = append(objects, r.buildEPPDeployment(infScheduler))
+	if pdb := r.buildEPPPDB(infScheduler); pdb != nil {
+		objects = append(objects, pdb)
+	}
+	objects = append(objects,
+		r.buildEPPService(infScheduler),
+		r.buildInferencePool(infScheduler),
+	)
+	if infScheduler.Spec.Gateway.ExistingGatewayRef == nil {
+		objects = append(objects, r.buildGateway(infScheduler))
+	}
+	objects = append(objects, r.buildHTTPRoute(infScheduler))
+	if infScheduler.Spec.Gateway.HealthCheckRoute {
+		objects = append(objects, r.buildHealthCheckHTTPRoute(infScheduler))
+	}
+	if tls := infScheduler.Spec.Gateway.TLS; tls != nil && tls.HTTPRedirect {
+		objects = append(objects, r.buildHTTPSRedirectHTTPRoute(infScheduler))
+	}
+	if monitoring := infScheduler.Spec.Monitoring; monitoring != nil && monitoring.PrometheusRule {
+		objects = append(objects, r.buildPrometheusRule(infScheduler))
+	}
+	if serviceMonitor := r.buildServiceMonitor(infScheduler); serviceMonitor != nil {
+		objects = append(objects, serviceMonitor)
+	}
+	if dashboard := r.buildDashboardConfigMap(infScheduler); dashboard != nil {
+		objects = append(objects, dashboard)
+	}
+
+	for _, obj := range objects {
+		setManagedMetadata(obj)
+	}
+	return objects
+}
+
+// RenderManifests renders the child objects of an InferenceScheduler as a multi-document YAML
+// stream, for GitOps workflows that commit the generated manifests instead of running the operator
+// The spec is rendered as given; CRD schema defaults must already be applied
+func RenderManifests(infScheduler *llmv1alpha1.InferenceScheduler) ([]byte, error) {
+	var out bytes.Buffer
+	for i, obj := range BuildChildObjects(infScheduler) {
+		content, err := toManifest(obj)
+		if err != nil {
+			return nil, err
+		}
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(data)
+	}
+	return out.Bytes(), nil
+}
+
+// toManifest converts an object to its map form with apiVersion and kind set and the
+// server-populated fields left out
+func toManifest(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, clientgoscheme.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "spec", "template", "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u.Object, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+var _ = Describe("Manifest rendering", func() {
+	var infScheduler *llmv1alpha1.InferenceScheduler
+
+	BeforeEach(func() {
+		infScheduler = &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-scheduler",
+				Namespace: "default",
+			},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{
+					ModelName:           "Qwen/Qwen2.5-0.5B-Instruct",
+					Replicas:            2,
+					Port:                8000,
+					EnablePrefixCaching: true,
+					HFTokenSecretName:   "hf-token",
+					Labels: map[string]string{
+						"team": "inference",
+						"env":  "test",
+					},
+				},
+				EndpointPicker: llmv1alpha1.EndpointPickerSpec{
+					Replicas: 1,
+					GRPCPort: 9002,
+				},
+				Gateway: llmv1alpha1.GatewaySpec{
+					ClassName:    "kgateway",
+					ListenerPort: 80,
+				},
+			},
+		}
+	})
+
+	// documents splits a rendered stream into its YAML documents
+	documents := func(rendered []byte) []map[string]interface{} {
+		var docs []map[string]interface{}
+		for _, doc := range strings.Split(string(rendered), "---\n") {
+			content := map[string]interface{}{}
+			Expect(yaml.Unmarshal([]byte(doc), &content)).To(Succeed())
+			docs = append(docs, content)
+		}
+		return docs
+	}
+
+	It("should render identical output for the same spec", func() {
+		first, err := RenderManifests(infScheduler)
+		Expect(err).NotTo(HaveOccurred())
+		for range 10 {
+			again, err := RenderManifests(infScheduler.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(again)).To(Equal(string(first)))
+		}
+	})
+
+	It("should render every child object in apply order", func() {
+		rendered, err := RenderManifests(infScheduler)
+		Expect(err).NotTo(HaveOccurred())
+
+		var kinds []string
+		for _, doc := range documents(rendered) {
+			Expect(doc).To(HaveKey("apiVersion"))
+			Expect(doc).NotTo(HaveKey("status"))
+			Expect(doc["metadata"]).NotTo(HaveKey("creationTimestamp"))
+			Expect(doc["metadata"]).To(HaveKeyWithValue("namespace", "default"))
+			kinds = append(kinds, doc["kind"].(string))
+		}
+		Expect(kinds).To(Equal([]string{
+			"Deployment", "Service", "ServiceAccount", "Role", "RoleBinding", "ConfigMap",
+			"Deployment", "Service", "InferencePool", "Gateway", "HTTPRoute",
+		}))
+	})
+
+	It("should include the health check route when enabled", func() {
+		infScheduler.Spec.Gateway.HealthCheckRoute = true
+
+		objects := BuildChildObjects(infScheduler)
+		Expect(objects).To(HaveLen(12))
+		Expect(objects[11].GetName()).To(Equal("test-scheduler-health-route"))
+	})
+
+	It("should mark every object as managed by the operator", func() {
+		for _, obj := range BuildChildObjects(infScheduler) {
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(managedByLabel, managedByValue))
+			Expect(obj.GetAnnotations()).To(HaveKeyWithValue(operatorVersionAnnotation, Version))
+		}
+
+		// Selectors share their map with the builder's labels and must stay unchanged
+		deployment := BuildChildObjects(infScheduler)[0].(*appsv1.Deployment)
+		Expect(deployment.Spec.Selector.MatchLabels).NotTo(HaveKey(managedByLabel))
+	})
+})
@@ -17,18 +17,135 @@ limitations under the License.
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
 )
 
+// ResourceDefaults are the CPU and memory requests and limits of the containers in the operator's
+// pods, for what their spec leaves unset. Namespaces with a ResourceQuota on requests or limits
+// reject pods that leave them unset
+type ResourceDefaults struct {
+	// ModelServer applies to the vLLM container and the model preload init container
+	ModelServer corev1.ResourceRequirements
+
+	// EndpointPicker applies to the EPP container
+	EndpointPicker corev1.ResourceRequirements
+
+	// Containers applies to the init containers and sidecars the user adds to the model server
+	Containers corev1.ResourceRequirements
+}
+
+// DefaultResources returns the defaults used unless the reconciler is configured with others.
+// The model server gets no default limits: what a model needs varies too much for a limit that
+// would not OOM-kill large models
+func DefaultResources() ResourceDefaults {
+	return ResourceDefaults{
+		ModelServer: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+		EndpointPicker: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+		Containers: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+	}
+}
+
+// resourceDefaults returns the configured resource defaults, or DefaultResources
+func (r *InferenceSchedulerReconciler) resourceDefaults() ResourceDefaults {
+	if r.ResourceDefaults != nil {
+		return *r.ResourceDefaults
+	}
+	return DefaultResources()
+}
+
+// withDefaultResources fills in the default requests and limits the user did not set. A default
+// request is capped at the user's limit, and a default limit is raised to the request
+func withDefaultResources(resources corev1.ResourceRequirements, requests, limits corev1.ResourceList) corev1.ResourceRequirements {
+	result := *resources.DeepCopy()
+	if result.Requests == nil {
+		result.Requests = corev1.ResourceList{}
+	}
+	if result.Limits == nil {
+		result.Limits = corev1.ResourceList{}
+	}
+
+	for name, request := range requests {
+		if _, ok := result.Requests[name]; ok {
+			continue
+		}
+		if limit, ok := result.Limits[name]; ok && limit.Cmp(request) < 0 {
+			request = limit
+		}
+		result.Requests[name] = request
+	}
+	for name, limit := range limits {
+		if _, ok := result.Limits[name]; ok {
+			continue
+		}
+		if request := result.Requests[name]; request.Cmp(limit) > 0 {
+			limit = request
+		}
+		result.Limits[name] = limit
+	}
+
+	return result
+}
+
+// imagePullSecrets returns the component's own pull secrets, or the shared ones if it has none
+func imagePullSecrets(infScheduler *llmv1alpha1.InferenceScheduler, component []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	if len(component) > 0 {
+		return component
+	}
+	return infScheduler.Spec.ImagePullSecrets
+}
+
+// setManagedMetadata marks obj as managed by this operator version. The label map is copied,
+// since builders share it with selectors that must not change
+func setManagedMetadata(obj client.Object) {
+	obj.SetLabels(mergeStringMaps(obj.GetLabels(), map[string]string{managedByLabel: managedByValue}))
+	obj.SetAnnotations(mergeStringMaps(obj.GetAnnotations(), map[string]string{operatorVersionAnnotation: Version}))
+}
+
 // buildModelServerDeployment creates a Deployment for the model server (vLLM)
 func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *llmv1alpha1.InferenceScheduler) *appsv1.Deployment {
 	modelName := sanitizeName(infScheduler.Spec.ModelServer.ModelName)
@@ -37,7 +154,6 @@ func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *
 		"app":                         "vllm",
 		"model":                       modelName,
 		"app.kubernetes.io/name":      "model-server",
-		"app.kubernetes.io/instance":  infScheduler.Name,
 		"app.kubernetes.io/component": "inference",
 	}
 
@@ -45,6 +161,8 @@ func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *
 	for k, v := range infScheduler.Spec.ModelServer.Labels {
 		labels[k] = v
 	}
+	// The Service and InferencePool select on the instance, so it cannot be overridden
+	labels[instanceLabel] = infScheduler.Name
 
 	replicas := getDefaultInt32(&infScheduler.Spec.ModelServer.Replicas, 2)
 	image := getDefaultString(infScheduler.Spec.ModelServer.Image, defaultModelServerImage)
@@ -56,6 +174,12 @@ func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *
 		fmt.Sprintf("--port=%d", port),
 	}
 
+	// vLLM takes all aliases as values of a single flag; repeating the flag keeps only the last one
+	if len(infScheduler.Spec.ModelServer.ServedModelNames) > 0 {
+		args = append(args, "--served-model-name")
+		args = append(args, infScheduler.Spec.ModelServer.ServedModelNames...)
+	}
+
 	if infScheduler.Spec.ModelServer.EnablePrefixCaching {
 		args = append(args, "--enable-prefix-caching")
 	}
@@ -63,14 +187,31 @@ func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *
 	gpuUtil := getDefaultFloat64(infScheduler.Spec.ModelServer.GPUMemoryUtilization, 0.9)
 	args = append(args, fmt.Sprintf("--gpu-memory-utilization=%.2f", gpuUtil))
 
+	if infScheduler.Spec.ModelServer.DownloadDir != "" {
+		args = append(args, fmt.Sprintf("--download-dir=%s", infScheduler.Spec.ModelServer.DownloadDir))
+	}
+
+	if infScheduler.Spec.ModelServer.DisableLogRequests {
+		args = append(args, "--disable-log-requests")
+	}
+
+	if tensorParallelSize := infScheduler.Spec.ModelServer.TensorParallelSize; tensorParallelSize != nil {
+		args = append(args, fmt.Sprintf("--tensor-parallel-size=%d", *tensorParallelSize))
+	}
+
+	args = mergeArgs(args, infScheduler.Spec.ModelServer.ExtraArgs)
+
+	defaults := r.resourceDefaults()
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-vllm", infScheduler.Name),
-			Namespace: infScheduler.Namespace,
-			Labels:    labels,
+			Name:        fmt.Sprintf("%s-vllm", infScheduler.Name),
+			Namespace:   infScheduler.Namespace,
+			Labels:      mergeStringMaps(infScheduler.Spec.ModelServer.DeploymentLabels, labels),
+			Annotations: mergeStringMaps(infScheduler.Spec.ModelServer.Annotations, infScheduler.Spec.ModelServer.DeploymentAnnotations),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
+			Paused:   infScheduler.Spec.ModelServer.PauseRollout,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
@@ -91,20 +232,9 @@ func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
-							Resources: infScheduler.Spec.ModelServer.Resources,
-							Env: []corev1.EnvVar{
-								{
-									Name: "HF_TOKEN",
-									ValueFrom: &corev1.EnvVarSource{
-										SecretKeyRef: &corev1.SecretKeySelector{
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: infScheduler.Spec.ModelServer.HFTokenSecretName,
-											},
-											Key: "token",
-										},
-									},
-								},
-							},
+							Resources: withDefaultResources(infScheduler.Spec.ModelServer.Resources, defaults.ModelServer.Requests, modelServerLimits(infScheduler, defaults.ModelServer.Limits)),
+							// Surfaces the end of the log, e.g. a rejected HF token, in the pod status
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
 				},
@@ -112,37 +242,726 @@ func (r *InferenceSchedulerReconciler) buildModelServerDeployment(infScheduler *
 		},
 	}
 
+	podSpec := &deployment.Spec.Template.Spec
+	// Public models and local model paths need no token
+	if name := infScheduler.Spec.ModelServer.HFTokenSecretName; name != "" {
+		optional := true
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name: "HF_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					Key:                  "token",
+					Optional:             &optional,
+				},
+			},
+		})
+	}
+
+	vllmHealth := corev1.ProbeHandler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path: "/health",
+			Port: intstr.FromInt(int(port)),
+		},
+	}
+	// Loading weights can take many minutes, liveness checks only start once /health succeeds
+	podSpec.Containers[0].StartupProbe = buildProbe(corev1.Probe{
+		ProbeHandler:        vllmHealth,
+		InitialDelaySeconds: 30,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+		FailureThreshold:    90,
+	}, infScheduler.Spec.ModelServer.StartupProbe)
+	podSpec.Containers[0].LivenessProbe = buildProbe(corev1.Probe{
+		ProbeHandler:     vllmHealth,
+		PeriodSeconds:    10,
+		TimeoutSeconds:   5,
+		FailureThreshold: 3,
+	}, infScheduler.Spec.ModelServer.LivenessProbe)
+	readinessProbe := corev1.Probe{
+		ProbeHandler:     vllmHealth,
+		PeriodSeconds:    5,
+		TimeoutSeconds:   5,
+		FailureThreshold: 3,
+	}
+	if warmup := infScheduler.Spec.ModelServer.Warmup; warmup != nil {
+		readinessProbe.ProbeHandler = buildWarmupProbeHandler(infScheduler, port)
+		readinessProbe.TimeoutSeconds = getDefaultInt32(warmup.TimeoutSeconds, 60)
+	}
+	podSpec.Containers[0].ReadinessProbe = buildProbe(readinessProbe, infScheduler.Spec.ModelServer.ReadinessProbe)
+
+	// Additional tokens are read by the model server alongside HF_TOKEN
+	for _, token := range infScheduler.Spec.ModelServer.AdditionalTokenSecrets {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name: token.EnvName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: token.SecretName,
+					},
+					Key: getDefaultString(token.Key, "token"),
+				},
+			},
+		})
+	}
+	// The preloaded weights reach vLLM through the cache volume, an emptyDir lives as long as the pod
+	if cache := infScheduler.Spec.ModelServer.ModelCache; cache != nil || infScheduler.Spec.ModelServer.PreloadModel != nil {
+		mountPath := defaultModelCachePath
+		volumeSource := corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		if cache != nil {
+			mountPath = getDefaultString(cache.MountPath, defaultModelCachePath)
+			volumeSource = corev1.VolumeSource{
+				PersistentVolumeClaim: cache.PersistentVolumeClaim.DeepCopy(),
+				NFS:                   cache.NFS.DeepCopy(),
+				CSI:                   cache.CSI.DeepCopy(),
+			}
+		}
+		if cache != nil && cache.Claim != nil {
+			volumeSource.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: fmt.Sprintf("%s-model-cache", infScheduler.Name),
+			}
+		}
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         "model-cache",
+			VolumeSource: volumeSource,
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "model-cache",
+			MountPath: mountPath,
+		})
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name:  "HF_HOME",
+			Value: mountPath,
+		})
+	}
+	if infScheduler.Spec.ModelServer.LogFormat == logFormatJSON {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "logging-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: fmt.Sprintf("%s-vllm-logging", infScheduler.Name),
+					},
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "logging-config",
+			MountPath: modelServerLoggingPath,
+			ReadOnly:  true,
+		})
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name:  "VLLM_LOGGING_CONFIG_PATH",
+			Value: modelServerLoggingPath + "/logging.json",
+		})
+	}
+	// The HorizontalPodAutoscaler owns the replica count, createOrUpdate keeps the current one
+	if infScheduler.Spec.ModelServer.Autoscaling != nil {
+		deployment.Spec.Replicas = nil
+	}
+
+	gracePeriod := modelServerTerminationGracePeriod(infScheduler.Spec.ModelServer)
+	podSpec.TerminationGracePeriodSeconds = &gracePeriod
+	// The endpoint is removed from the pool while the container sleeps, vLLM is only signalled after
+	if sleep := infScheduler.Spec.ModelServer.PreStopSleepSeconds; sleep != nil {
+		podSpec.Containers[0].Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"sleep", strconv.Itoa(int(*sleep))}},
+			},
+		}
+	}
+
+	podSpec.ImagePullSecrets = imagePullSecrets(infScheduler, infScheduler.Spec.ModelServer.ImagePullSecrets)
+	podSpec.NodeSelector = infScheduler.Spec.ModelServer.NodeSelector
+	podSpec.Tolerations = infScheduler.Spec.ModelServer.Tolerations
+	podSpec.Affinity = infScheduler.Spec.ModelServer.Affinity.DeepCopy()
+	podSpec.TopologySpreadConstraints = buildTopologySpreadConstraints(infScheduler.Spec.ModelServer.TopologySpreadConstraints, deployment.Spec.Selector.MatchLabels)
+	if infScheduler.Spec.ModelServer.SpreadAcrossNodes {
+		spread := true
+		for _, constraint := range podSpec.TopologySpreadConstraints {
+			if constraint.TopologyKey == corev1.LabelHostname {
+				spread = false
+			}
+		}
+		if spread {
+			podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+				MaxSkew:           1,
+				TopologyKey:       corev1.LabelHostname,
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: deployment.Spec.Selector.MatchLabels},
+			})
+		}
+	}
+	podSpec.SecurityContext = buildPodSecurityContext(infScheduler.Spec.ModelServer.PodSecurityContext, true)
+	if podSpec.SecurityContext.FSGroup == nil {
+		podSpec.SecurityContext.FSGroup = infScheduler.Spec.ModelServer.FSGroup
+	}
+	// The vLLM image runs as root unless told otherwise. The group also owns the pod's volumes, so
+	// the non-root user can write to a mounted model cache
+	if infScheduler.Spec.ModelServer.PodSecurityContext == nil {
+		user, group := int64(modelServerUser), int64(modelServerGroup)
+		podSpec.SecurityContext.RunAsUser = &user
+		podSpec.SecurityContext.RunAsGroup = &group
+		if podSpec.SecurityContext.FSGroup == nil {
+			podSpec.SecurityContext.FSGroup = &group
+		}
+	}
+	podSpec.Containers[0].SecurityContext = buildContainerSecurityContext(infScheduler.Spec.ModelServer.SecurityContext, true)
+	// vLLM, Triton and torch write caches below HOME and temporary files to /tmp
+	if readOnly := podSpec.Containers[0].SecurityContext.ReadOnlyRootFilesystem; readOnly != nil && *readOnly {
+		for _, dir := range []struct{ name, path string }{{"tmp", "/tmp"}, {"home", modelServerHomePath}} {
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name:         dir.name,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+			podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+				Name:      dir.name,
+				MountPath: dir.path,
+			})
+		}
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name:  "HOME",
+			Value: modelServerHomePath,
+		})
+	}
+
+	// User-supplied containers are copied as-is, apart from the resources they leave unset
+	for _, initContainer := range infScheduler.Spec.ModelServer.InitContainers {
+		container := *initContainer.DeepCopy()
+		container.Resources = withDefaultResources(container.Resources, defaults.Containers.Requests, defaults.Containers.Limits)
+		podSpec.InitContainers = append(podSpec.InitContainers, container)
+	}
+	for _, sidecar := range infScheduler.Spec.ModelServer.Sidecars {
+		container := *sidecar.DeepCopy()
+		container.Resources = withDefaultResources(container.Resources, defaults.Containers.Requests, defaults.Containers.Limits)
+		podSpec.Containers = append(podSpec.Containers, container)
+	}
+
+	podSpec.Containers[0].Env = mergeEnv(podSpec.Containers[0].Env, infScheduler.Spec.ModelServer.ExtraEnv)
+
+	// The preload runs last, after user init containers that may prepare the cache volume
+	if infScheduler.Spec.ModelServer.PreloadModel != nil {
+		podSpec.InitContainers = append(podSpec.InitContainers, buildModelPreloadContainer(infScheduler, podSpec.Containers[0], defaults.ModelServer))
+	}
+
+	if monitoring := infScheduler.Spec.Monitoring; monitoring != nil && monitoring.ScrapeAnnotations {
+		deployment.Spec.Template.Annotations = map[string]string{
+			prometheusScrapeAnnotation: "true",
+			prometheusPortAnnotation:   strconv.Itoa(int(port)),
+			prometheusPathAnnotation:   "/metrics",
+		}
+	}
+
 	return deployment
 }
 
+// mergeArgs appends extra to the generated args, dropping the generated flags that extra sets
+// again together with their values, so a flag never appears with two conflicting values
+func mergeArgs(args, extra []string) []string {
+	if len(extra) == 0 {
+		return args
+	}
+
+	overridden := map[string]bool{}
+	for _, arg := range extra {
+		if name, ok := flagName(arg); ok {
+			overridden[name] = true
+		}
+	}
+
+	merged := make([]string, 0, len(args)+len(extra))
+	dropping := false
+	for _, arg := range args {
+		if name, ok := flagName(arg); ok {
+			dropping = overridden[name]
+		}
+		// Values following a dropped flag, e.g. the aliases of --served-model-name, go with it
+		if !dropping {
+			merged = append(merged, arg)
+		}
+	}
+	return append(merged, extra...)
+}
+
+// flagName returns the name of a "--name" or "--name=value" argument
+func flagName(arg string) (string, bool) {
+	if !strings.HasPrefix(arg, "--") {
+		return "", false
+	}
+	name, _, _ := strings.Cut(arg, "=")
+	return name, true
+}
+
+// mergeEnv replaces the variables of env that extra sets again and appends the others
+func mergeEnv(env, extra []corev1.EnvVar) []corev1.EnvVar {
+	for _, variable := range extra {
+		replaced := false
+		for i := range env {
+			if env[i].Name == variable.Name {
+				env[i] = *variable.DeepCopy()
+				replaced = true
+			}
+		}
+		if !replaced {
+			env = append(env, *variable.DeepCopy())
+		}
+	}
+	return env
+}
+
+// buildModelPreloadContainer creates the init container that downloads the model into the cache
+// volume of the vLLM container. It gets the whole vLLM environment, so the HF token, cache paths
+// and settings such as proxies or HF_ENDPOINT from extraEnv apply to the download too
+func buildModelPreloadContainer(infScheduler *llmv1alpha1.InferenceScheduler, vllm corev1.Container, defaults corev1.ResourceRequirements) corev1.Container {
+	preload := infScheduler.Spec.ModelServer.PreloadModel
+	args := []string{"download", infScheduler.Spec.ModelServer.ModelName}
+	// vLLM looks for the weights in --download-dir instead of the HuggingFace cache when it is set
+	if downloadDir := infScheduler.Spec.ModelServer.DownloadDir; downloadDir != "" {
+		args = append(args, fmt.Sprintf("--cache-dir=%s", downloadDir))
+	}
+
+	env := make([]corev1.EnvVar, 0, len(vllm.Env))
+	for _, variable := range vllm.Env {
+		env = append(env, *variable.DeepCopy())
+	}
+	var volumeMounts []corev1.VolumeMount
+	for _, mount := range vllm.VolumeMounts {
+		switch mount.Name {
+		case "model-cache", "tmp", "home":
+			volumeMounts = append(volumeMounts, *mount.DeepCopy())
+		}
+	}
+
+	return corev1.Container{
+		Name:            "preload-model",
+		Image:           getDefaultString(preload.Image, vllm.Image),
+		Command:         []string{"huggingface-cli"},
+		Args:            args,
+		Env:             env,
+		VolumeMounts:    volumeMounts,
+		Resources:       withDefaultResources(preload.Resources, defaults.Requests, defaults.Limits),
+		SecurityContext: vllm.SecurityContext.DeepCopy(),
+		// Surfaces a rejected HF token in the pod status, like the vLLM container
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+// buildPodSecurityContext returns a copy of the configured pod security context, or the default
+// one with the RuntimeDefault seccomp profile. runAsNonRoot is only defaulted if the pod runs as
+// a non-root user
+func buildPodSecurityContext(configured *corev1.PodSecurityContext, runAsNonRoot bool) *corev1.PodSecurityContext {
+	if configured != nil {
+		return configured.DeepCopy()
+	}
+	securityContext := &corev1.PodSecurityContext{
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	if runAsNonRoot {
+		securityContext.RunAsNonRoot = &runAsNonRoot
+	}
+	return securityContext
+}
+
+// buildContainerSecurityContext returns a copy of the configured container security context, or
+// the default one that disallows privilege escalation and drops all capabilities, as the
+// restricted Pod Security Standard requires
+func buildContainerSecurityContext(configured *corev1.SecurityContext, readOnlyRootFilesystem bool) *corev1.SecurityContext {
+	if configured != nil {
+		return configured.DeepCopy()
+	}
+	allowPrivilegeEscalation := false
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+	if readOnlyRootFilesystem {
+		securityContext.ReadOnlyRootFilesystem = &readOnlyRootFilesystem
+	}
+	return securityContext
+}
+
+// buildTopologySpreadConstraints returns copies of the configured constraints, with the label
+// selector of constraints that have none set to the component's pod selector
+func buildTopologySpreadConstraints(configured []corev1.TopologySpreadConstraint, selector map[string]string) []corev1.TopologySpreadConstraint {
+	var constraints []corev1.TopologySpreadConstraint
+	for _, constraint := range configured {
+		constraint := *constraint.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{MatchLabels: selector}
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
+}
+
+// modelServerTerminationGracePeriod returns the grace period of the model server pods
+func modelServerTerminationGracePeriod(modelServer llmv1alpha1.ModelServerSpec) int64 {
+	if modelServer.TerminationGracePeriodSeconds != nil {
+		return *modelServer.TerminationGracePeriodSeconds
+	}
+	return defaultModelServerTerminationGracePeriod
+}
+
+// modelServerLimits returns the default limits of the vLLM container. With tensor parallelism
+// they add one GPU per shard, unless the user requests GPUs explicitly
+func modelServerLimits(infScheduler *llmv1alpha1.InferenceScheduler, defaults corev1.ResourceList) corev1.ResourceList {
+	tensorParallelSize := infScheduler.Spec.ModelServer.TensorParallelSize
+	resources := infScheduler.Spec.ModelServer.Resources
+	_, inRequests := resources.Requests[gpuResourceName]
+	_, inLimits := resources.Limits[gpuResourceName]
+	if tensorParallelSize == nil || inRequests || inLimits {
+		return defaults
+	}
+
+	// Extended resources are requested at their limit
+	limits := defaults.DeepCopy()
+	if limits == nil {
+		limits = corev1.ResourceList{}
+	}
+	limits[gpuResourceName] = *resource.NewQuantity(int64(*tensorParallelSize), resource.DecimalSI)
+	return limits
+}
+
+// buildModelServerWorkload creates the Deployment or StatefulSet that runs the model server
+func (r *InferenceSchedulerReconciler) buildModelServerWorkload(infScheduler *llmv1alpha1.InferenceScheduler) client.Object {
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		return r.buildModelServerStatefulSet(infScheduler)
+	}
+	return r.buildModelServerDeployment(infScheduler)
+}
+
+// buildModelServerStatefulSet creates a StatefulSet for the model server with the same pod
+// template as the Deployment, governed by the headless model server Service
+func (r *InferenceSchedulerReconciler) buildModelServerStatefulSet(infScheduler *llmv1alpha1.InferenceScheduler) *appsv1.StatefulSet {
+	deployment := r.buildModelServerDeployment(infScheduler)
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: deployment.ObjectMeta,
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    deployment.Spec.Replicas,
+			ServiceName: fmt.Sprintf("%s-vllm-headless", infScheduler.Name),
+			Selector:    deployment.Spec.Selector,
+			Template:    deployment.Spec.Template,
+		},
+	}
+
+	// StatefulSets have no Paused field, a partition at the replica count keeps every pod on its revision
+	if infScheduler.Spec.ModelServer.PauseRollout {
+		// An autoscaled StatefulSet never grows beyond MaxReplicas
+		var partition int32
+		if autoscaling := infScheduler.Spec.ModelServer.Autoscaling; autoscaling != nil {
+			partition = autoscaling.MaxReplicas
+		} else {
+			partition = *deployment.Spec.Replicas
+		}
+		statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: &partition,
+			},
+		}
+	}
+
+	return statefulSet
+}
+
+// buildModelServerHPA creates the HorizontalPodAutoscaler that scales the model server workload
+func (r *InferenceSchedulerReconciler) buildModelServerHPA(infScheduler *llmv1alpha1.InferenceScheduler) *autoscalingv2.HorizontalPodAutoscaler {
+	autoscaling := infScheduler.Spec.ModelServer.Autoscaling
+	minReplicas := getDefaultInt32(autoscaling.MinReplicas, 1)
+	name := fmt.Sprintf("%s-vllm", infScheduler.Name)
+
+	kind := "Deployment"
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		kind = workloadStatefulSet
+	}
+
+	metrics := make([]autoscalingv2.MetricSpec, 0, len(autoscaling.Metrics))
+	for _, metric := range autoscaling.Metrics {
+		metrics = append(metrics, *metric.DeepCopy())
+	}
+	if len(metrics) == 0 {
+		targetUtilization := int32(80)
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &targetUtilization,
+				},
+			},
+		})
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: infScheduler.Namespace,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       kind,
+				Name:       name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+// buildModelServerPDB creates the PodDisruptionBudget of the model server pods, or returns nil if
+// none is wanted
+func (r *InferenceSchedulerReconciler) buildModelServerPDB(infScheduler *llmv1alpha1.InferenceScheduler) *policyv1.PodDisruptionBudget {
+	// The StatefulSet shares the Deployment's selector
+	selector := r.buildModelServerDeployment(infScheduler).Spec.Selector
+	return buildPodDisruptionBudget(fmt.Sprintf("%s-vllm", infScheduler.Name), infScheduler.Namespace,
+		infScheduler.Spec.ModelServer.PodDisruptionBudget, maxModelServerReplicas(infScheduler), selector)
+}
+
+// buildEPPPDB creates the PodDisruptionBudget of the EPP pods, or returns nil if none is wanted
+func (r *InferenceSchedulerReconciler) buildEPPPDB(infScheduler *llmv1alpha1.InferenceScheduler) *policyv1.PodDisruptionBudget {
+	selector := r.buildEPPDeployment(infScheduler).Spec.Selector
+	return buildPodDisruptionBudget(fmt.Sprintf("%s-epp", infScheduler.Name), infScheduler.Namespace,
+		infScheduler.Spec.EndpointPicker.PodDisruptionBudget, infScheduler.Spec.EndpointPicker.Replicas, selector)
+}
+
+// buildPodDisruptionBudget creates a PodDisruptionBudget for the pods matching selector. Without an
+// explicit budget one pod may be unavailable at a time, which a single replica does not need
+func buildPodDisruptionBudget(name, namespace string, spec *llmv1alpha1.PodDisruptionBudgetSpec, replicas int32, selector *metav1.LabelSelector) *policyv1.PodDisruptionBudget {
+	if spec == nil {
+		return nil
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: selector.DeepCopy(),
+		},
+	}
+	switch {
+	case spec.MinAvailable != nil:
+		pdb.Spec.MinAvailable = spec.MinAvailable
+	case spec.MaxUnavailable != nil:
+		pdb.Spec.MaxUnavailable = spec.MaxUnavailable
+	case replicas > 1:
+		maxUnavailable := intstr.FromInt32(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	default:
+		return nil
+	}
+	return pdb
+}
+
+// extraModelServerName returns the name of the workload and Service of an extra model server
+func extraModelServerName(infScheduler *llmv1alpha1.InferenceScheduler, extra llmv1alpha1.ExtraModelServerSpec) string {
+	return fmt.Sprintf("%s-%s-vllm", infScheduler.Name, extra.Name)
+}
+
+// extraModelServerScheduler returns a copy of the InferenceScheduler whose ModelServer is the
+// extra model server, so it can be built by the model server builders
+func extraModelServerScheduler(infScheduler *llmv1alpha1.InferenceScheduler, extra llmv1alpha1.ExtraModelServerSpec) *llmv1alpha1.InferenceScheduler {
+	variant := infScheduler.DeepCopy()
+	modelServer := &variant.Spec.ModelServer
+	modelServer.ModelName = extra.ModelName
+	modelServer.Replicas = getDefaultInt32(extra.Replicas, 1)
+	// These only apply to the primary model server
+	modelServer.ReplicasFrom = nil
+	modelServer.Autoscaling = nil
+	modelServer.PodDisruptionBudget = nil
+	modelServer.PinnedRevision = ""
+
+	if extra.Image != "" {
+		modelServer.Image = extra.Image
+	}
+	if extra.Resources != nil {
+		modelServer.Resources = *extra.Resources.DeepCopy()
+	}
+	if extra.ExtraArgs != nil {
+		modelServer.ExtraArgs = extra.ExtraArgs
+	}
+	if extra.ExtraEnv != nil {
+		modelServer.ExtraEnv = extra.ExtraEnv
+	}
+	return variant
+}
+
+// buildExtraModelServerObjects creates the workload and Services of an extra model server. They
+// are built like those of the primary model server, then renamed and labeled with modelServerLabel
+func (r *InferenceSchedulerReconciler) buildExtraModelServerObjects(infScheduler *llmv1alpha1.InferenceScheduler, extra llmv1alpha1.ExtraModelServerSpec) []client.Object {
+	variant := extraModelServerScheduler(infScheduler, extra)
+	name := extraModelServerName(infScheduler, extra)
+
+	workload := r.buildModelServerWorkload(variant)
+	workload.SetName(name)
+	workload.SetLabels(mergeStringMaps(workload.GetLabels(), map[string]string{modelServerLabel: extra.Name}))
+	// The selector, pod labels and default topology spread selectors share one map
+	switch workload := workload.(type) {
+	case *appsv1.Deployment:
+		workload.Spec.Selector.MatchLabels[modelServerLabel] = extra.Name
+		workload.Spec.Template.Labels[modelServerLabel] = extra.Name
+	case *appsv1.StatefulSet:
+		workload.Spec.Selector.MatchLabels[modelServerLabel] = extra.Name
+		workload.Spec.Template.Labels[modelServerLabel] = extra.Name
+		workload.Spec.ServiceName = fmt.Sprintf("%s-headless", name)
+	}
+
+	services := []*corev1.Service{r.buildModelServerService(variant)}
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		services = append(services, r.buildModelServerHeadlessService(variant))
+	}
+
+	objects := []client.Object{workload}
+	for _, service := range services {
+		service.Name = strings.Replace(service.Name, fmt.Sprintf("%s-vllm", infScheduler.Name), name, 1)
+		service.Labels[modelServerLabel] = extra.Name
+		service.Spec.Selector[modelServerLabel] = extra.Name
+		// The node port of the primary model server is taken, let Kubernetes allocate one
+		service.Spec.Ports[0].NodePort = 0
+		objects = append(objects, service)
+	}
+	return objects
+}
+
+// buildModelServerHeadlessService creates the headless Service that gives StatefulSet model server
+// pods their stable DNS names
+func (r *InferenceSchedulerReconciler) buildModelServerHeadlessService(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.Service {
+	service := r.buildModelServerService(infScheduler)
+	service.Name = fmt.Sprintf("%s-vllm-headless", infScheduler.Name)
+	// A headless Service has no cluster IP to expose on a node or load balancer
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.Ports[0].NodePort = 0
+	service.Spec.ClusterIP = corev1.ClusterIPNone
+	// Peers of a tensor-parallel group must resolve each other before they become ready
+	service.Spec.PublishNotReadyAddresses = true
+
+	return service
+}
+
+// modelServerJSONLoggingConfig is a Python logging dictConfig that writes every log record as a
+// JSON object to stdout. python-json-logger ships with vLLM
+const modelServerJSONLoggingConfig = `{
+  "version": 1,
+  "disable_existing_loggers": false,
+  "formatters": {
+    "json": {
+      "class": "pythonjsonlogger.jsonlogger.JsonFormatter",
+      "format": "%(asctime)s %(levelname)s %(name)s %(message)s"
+    }
+  },
+  "handlers": {
+    "console": {
+      "class": "logging.StreamHandler",
+      "formatter": "json",
+      "stream": "ext://sys.stdout"
+    }
+  },
+  "loggers": {
+    "vllm": {
+      "handlers": ["console"],
+      "level": "INFO",
+      "propagate": false
+    }
+  },
+  "root": {
+    "handlers": ["console"],
+    "level": "INFO"
+  }
+}
+`
+
+// buildModelCachePVC creates the PersistentVolumeClaim provisioned for the model cache
+func (r *InferenceSchedulerReconciler) buildModelCachePVC(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.PersistentVolumeClaim {
+	claim := infScheduler.Spec.ModelServer.ModelCache.Claim
+	accessMode := claim.AccessMode
+	if accessMode == "" {
+		accessMode = corev1.ReadWriteMany
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-model-cache", infScheduler.Name),
+			Namespace: infScheduler.Namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{accessMode},
+			StorageClassName: claim.StorageClassName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: claim.Size,
+				},
+			},
+		},
+	}
+}
+
+// buildModelServerLoggingConfigMap creates the ConfigMap with the vLLM logging config used for JSON logs
+func (r *InferenceSchedulerReconciler) buildModelServerLoggingConfigMap(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-vllm-logging", infScheduler.Name),
+			Namespace: infScheduler.Namespace,
+		},
+		Data: map[string]string{
+			"logging.json": modelServerJSONLoggingConfig,
+		},
+	}
+}
+
 // buildModelServerService creates a Service for the model server
 func (r *InferenceSchedulerReconciler) buildModelServerService(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.Service {
 	modelName := sanitizeName(infScheduler.Spec.ModelServer.ModelName)
 
+	// Schedulers serving the same model in one namespace must not select each other's pods
 	labels := map[string]string{
-		"app":   "vllm",
-		"model": modelName,
+		"app":         "vllm",
+		"model":       modelName,
+		instanceLabel: infScheduler.Name,
 	}
 
 	port := getDefaultInt32(&infScheduler.Spec.ModelServer.Port, defaultModelServerPort)
+	targetPort := getDefaultInt32(infScheduler.Spec.ModelServer.TargetPort, port)
+
+	portName := "http"
+	var appProtocol *string
+	serviceType := corev1.ServiceTypeClusterIP
+	var nodePort int32
+	if spec := infScheduler.Spec.ModelServer.Service; spec != nil {
+		portName = getDefaultString(spec.PortName, portName)
+		appProtocol = spec.AppProtocol
+		serviceType = corev1.ServiceType(getDefaultString(spec.Type, string(serviceType)))
+		if serviceType != corev1.ServiceTypeClusterIP && spec.NodePort != nil {
+			nodePort = *spec.NodePort
+		}
+	}
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-vllm", infScheduler.Name),
-			Namespace: infScheduler.Namespace,
-			Labels:    labels,
+			Name:        fmt.Sprintf("%s-vllm", infScheduler.Name),
+			Namespace:   infScheduler.Namespace,
+			Labels:      mergeStringMaps(labels),
+			Annotations: mergeStringMaps(infScheduler.Spec.ModelServer.Annotations),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: labels,
 			Ports: []corev1.ServicePort{
 				{
-					Name:       "http",
-					Port:       port,
-					TargetPort: intstr.FromInt(int(port)),
-					Protocol:   corev1.ProtocolTCP,
+					Name:        portName,
+					Port:        port,
+					TargetPort:  intstr.FromInt(int(targetPort)),
+					Protocol:    corev1.ProtocolTCP,
+					AppProtocol: appProtocol,
+					NodePort:    nodePort,
 				},
 			},
-			Type: corev1.ServiceTypeClusterIP,
+			Type: serviceType,
 		},
 	}
 
@@ -161,7 +980,7 @@ func (r *InferenceSchedulerReconciler) buildEPPServiceAccount(infScheduler *llmv
 
 // buildEPPRole creates a Role for EPP with permissions to list pods and get inferencepools
 func (r *InferenceSchedulerReconciler) buildEPPRole(infScheduler *llmv1alpha1.InferenceScheduler) *rbacv1.Role {
-	return &rbacv1.Role{
+	role := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-epp", infScheduler.Name),
 			Namespace: infScheduler.Namespace,
@@ -179,6 +998,25 @@ func (r *InferenceSchedulerReconciler) buildEPPRole(infScheduler *llmv1alpha1.In
 			},
 		},
 	}
+
+	// The operator can only grant these because its own ClusterRole includes them
+	if spec := infScheduler.Spec.EndpointPicker.RBAC; spec != nil {
+		if spec.LeaderElection {
+			role.Rules = append(role.Rules, rbacv1.PolicyRule{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			})
+		}
+		if spec.EndpointSlices {
+			role.Rules = append(role.Rules, rbacv1.PolicyRule{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "list", "watch"},
+			})
+		}
+	}
+	return role
 }
 
 // buildEPPRoleBinding creates a RoleBinding for EPP
@@ -203,50 +1041,116 @@ func (r *InferenceSchedulerReconciler) buildEPPRoleBinding(infScheduler *llmv1al
 	}
 }
 
+// activePlugins summarizes the plugins of a generated EPP config ConfigMap as type=weight, in
+// the order they are rendered
+func activePlugins(configMap *corev1.ConfigMap) []string {
+	var config struct {
+		Plugins []struct {
+			Type   string  `json:"type"`
+			Weight float64 `json:"weight"`
+		} `json:"plugins"`
+	}
+	// The config is rendered by buildEPPConfigMap, so it always parses
+	if err := yaml.Unmarshal([]byte(configMap.Data["plugins.yaml"]), &config); err != nil {
+		return nil
+	}
+
+	var plugins []string
+	for _, plugin := range config.Plugins {
+		plugins = append(plugins, fmt.Sprintf("%s=%.1f", plugin.Type, plugin.Weight))
+	}
+	return plugins
+}
+
 // buildEPPConfigMap creates a ConfigMap with EPP plugin configuration
+// Returns nil if the configuration is read from PluginConfigMapRef instead
 func (r *InferenceSchedulerReconciler) buildEPPConfigMap(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.ConfigMap {
+	if infScheduler.Spec.EndpointPicker.PluginConfigMapRef != nil {
+		return nil
+	}
+
 	// Build plugin configuration YAML
-	pluginConfig := `apiVersion: inference.networking.x-k8s.io/v1alpha1
+	configAPIVersion := getDefaultString(infScheduler.Spec.EndpointPicker.ConfigAPIVersion, defaultEPPConfigAPIVersion)
+	pluginConfig := fmt.Sprintf(`apiVersion: %s
 kind: EndpointPickerConfig
-plugins:`
+plugins:`, configAPIVersion)
 
 	// Load-aware scorer
 	if infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer != nil && infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer.Enabled {
-		weight := getDefaultFloat64(infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer.Weight, 1.0)
+		scorer := infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer
+		weight := getDefaultFloat64(scorer.Weight, 1.0)
+		params := mergeStringMaps(map[string]string{"queueThreshold": "128"}, scorer.Parameters)
+		if scorer.QueueThreshold != nil {
+			params["queueThreshold"] = strconv.Itoa(int(*scorer.QueueThreshold))
+		}
 		pluginConfig += fmt.Sprintf(`
   - type: load-aware-scorer
-    weight: %.1f
-    parameters:
-      queueThreshold: "%s"`,
-			weight,
-			getDefaultString(infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer.Parameters["queueThreshold"], "128"))
+    weight: %.1f`,
+			weight) + renderPluginParameters(params)
 	}
 
 	// Prefix cache scorer
 	if infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer != nil && infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer.Enabled {
-		weight := getDefaultFloat64(infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer.Weight, 2.0)
+		scorer := infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer
+		weight := getDefaultFloat64(scorer.Weight, 2.0)
+		params := mergeStringMaps(map[string]string{"cacheHitBonus": "1.0"}, scorer.Parameters)
+		if scorer.CacheHitBonus != nil {
+			params["cacheHitBonus"] = strconv.FormatFloat(*scorer.CacheHitBonus, 'f', -1, 64)
+		}
 		pluginConfig += fmt.Sprintf(`
   - type: prefix-cache-scorer
-    weight: %.1f
-    parameters:
-      cacheHitBonus: "%s"`,
-			weight,
-			getDefaultString(infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer.Parameters["cacheHitBonus"], "1.0"))
+    weight: %.1f`,
+			weight) + renderPluginParameters(params)
 	}
 
 	// KV cache utilization scorer
 	if infScheduler.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer != nil && infScheduler.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer.Enabled {
-		weight := getDefaultFloat64(infScheduler.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer.Weight, 1.0)
+		scorer := infScheduler.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer
+		weight := getDefaultFloat64(scorer.Weight, 1.0)
 		pluginConfig += fmt.Sprintf(`
   - type: kv-cache-utilization-scorer
     weight: %.1f`,
-			weight)
+			weight) + renderPluginParameters(scorer.Parameters)
 	}
 
+	// Session affinity scorer
+	if infScheduler.Spec.EndpointPicker.Plugins.SessionAffinityScorer != nil && infScheduler.Spec.EndpointPicker.Plugins.SessionAffinityScorer.Enabled {
+		scorer := infScheduler.Spec.EndpointPicker.Plugins.SessionAffinityScorer
+		weight := getDefaultFloat64(scorer.Weight, 1.0)
+		pluginConfig += fmt.Sprintf(`
+  - type: session-affinity-scorer
+    weight: %.1f`,
+			weight) + renderPluginParameters(scorer.Parameters)
+	}
+
+	// LoRA affinity scorer
+	if infScheduler.Spec.EndpointPicker.Plugins.LoraAffinityScorer != nil && infScheduler.Spec.EndpointPicker.Plugins.LoraAffinityScorer.Enabled {
+		scorer := infScheduler.Spec.EndpointPicker.Plugins.LoraAffinityScorer
+		weight := getDefaultFloat64(scorer.Weight, 1.0)
+		pluginConfig += fmt.Sprintf(`
+  - type: lora-affinity-scorer
+    weight: %.1f`,
+			weight) + renderPluginParameters(scorer.Parameters)
+	}
+
+	// Scorers without a typed field, in the order they are listed
+	for _, scorer := range infScheduler.Spec.EndpointPicker.Plugins.AdditionalScorers {
+		weight := getDefaultFloat64(scorer.Weight, 1.0)
+		pluginConfig += fmt.Sprintf(`
+  - type: %s
+    weight: %.1f`,
+			scorer.Type, weight) + renderPluginParameters(scorer.Parameters)
+	}
+
+	// The config is rendered deterministically, so the hash only changes with its content
+	sum := sha256.Sum256([]byte(pluginConfig))
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-epp-config", infScheduler.Name),
 			Namespace: infScheduler.Namespace,
+			Annotations: map[string]string{
+				configHashAnnotation: hex.EncodeToString(sum[:]),
+			},
 		},
 		Data: map[string]string{
 			"plugins.yaml": pluginConfig,
@@ -254,6 +1158,95 @@ plugins:`
 	}
 }
 
+// renderPluginParameters renders the parameters block of a plugin in plugins.yaml, with keys
+// sorted so the ConfigMap content is stable. Returns an empty string if there are no parameters
+func renderPluginParameters(params map[string]string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rendered := `
+    parameters:`
+	for _, k := range keys {
+		rendered += fmt.Sprintf(`
+      %s: %q`, k, params[k])
+	}
+	return rendered
+}
+
+// warmupScript checks /health once the pod is warmed up. Until then it sends a completion request
+// and records its success in a marker file, so the model only serves one warmup request per container
+const warmupScript = `import json, os, ssl, sys, urllib.request
+base, path, body, timeout = sys.argv[1], sys.argv[2], sys.argv[3], int(sys.argv[4])
+context = ssl._create_unverified_context() if base.startswith("https") else None
+marker = "/tmp/warmup-done"
+if os.path.exists(marker):
+    urllib.request.urlopen(base + "/health", timeout=timeout, context=context)
+    sys.exit(0)
+request = urllib.request.Request(base + path, data=body.encode(), headers={"Content-Type": "application/json"})
+urllib.request.urlopen(request, timeout=timeout, context=context)
+open(marker, "w").close()
+`
+
+// buildWarmupProbeHandler creates the exec handler of the model server readiness probe that
+// completes a warmup inference before the pod becomes Ready for the first time
+func buildWarmupProbeHandler(infScheduler *llmv1alpha1.InferenceScheduler, port int32) corev1.ProbeHandler {
+	warmup := infScheduler.Spec.ModelServer.Warmup
+
+	scheme := "http"
+	if probe := infScheduler.Spec.ModelServer.ReadinessProbe; probe != nil && probe.Scheme == string(corev1.URISchemeHTTPS) {
+		scheme = "https"
+	}
+	// vLLM only accepts the served model names once they are set
+	model := infScheduler.Spec.ModelServer.ModelName
+	if len(infScheduler.Spec.ModelServer.ServedModelNames) > 0 {
+		model = infScheduler.Spec.ModelServer.ServedModelNames[0]
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"model":      model,
+		"prompt":     getDefaultString(warmup.Prompt, "Hello"),
+		"max_tokens": 1,
+	})
+
+	return corev1.ProbeHandler{
+		Exec: &corev1.ExecAction{
+			Command: []string{
+				"python3", "-c", warmupScript,
+				fmt.Sprintf("%s://localhost:%d", scheme, port),
+				getDefaultString(warmup.Path, "/v1/completions"),
+				string(body),
+				strconv.Itoa(int(getDefaultInt32(warmup.TimeoutSeconds, 60))),
+			},
+		},
+	}
+}
+
+// buildProbe applies the user's overrides to a component's default probe.
+// Returns nil if the probe is disabled
+func buildProbe(defaults corev1.Probe, spec *llmv1alpha1.ProbeSpec) *corev1.Probe {
+	probe := defaults
+	if spec != nil {
+		if spec.Disabled {
+			return nil
+		}
+		probe.InitialDelaySeconds = getDefaultInt32(spec.InitialDelaySeconds, probe.InitialDelaySeconds)
+		probe.PeriodSeconds = getDefaultInt32(spec.PeriodSeconds, probe.PeriodSeconds)
+		probe.TimeoutSeconds = getDefaultInt32(spec.TimeoutSeconds, probe.TimeoutSeconds)
+		probe.FailureThreshold = getDefaultInt32(spec.FailureThreshold, probe.FailureThreshold)
+		// The handler is shared with the component's other probes, so change a copy
+		if probe.HTTPGet != nil && spec.Scheme != "" {
+			probe.HTTPGet = probe.HTTPGet.DeepCopy()
+			probe.HTTPGet.Scheme = corev1.URIScheme(spec.Scheme)
+		}
+	}
+	return &probe
+}
+
 // buildEPPDeployment creates a Deployment for EPP
 func (r *InferenceSchedulerReconciler) buildEPPDeployment(infScheduler *llmv1alpha1.InferenceScheduler) *appsv1.Deployment {
 	labels := map[string]string{
@@ -266,15 +1259,76 @@ func (r *InferenceSchedulerReconciler) buildEPPDeployment(infScheduler *llmv1alp
 	replicas := getDefaultInt32(&infScheduler.Spec.EndpointPicker.Replicas, 1)
 	image := getDefaultString(infScheduler.Spec.EndpointPicker.Image, defaultEPPImage)
 	grpcPort := getDefaultInt32(&infScheduler.Spec.EndpointPicker.GRPCPort, defaultEPPGRPCPort)
+	healthPort := getDefaultInt32(infScheduler.Spec.EndpointPicker.HealthPort, defaultEPPHealthPort)
+	metricsPort := getDefaultInt32(infScheduler.Spec.EndpointPicker.MetricsPort, defaultEPPMetricsPort)
+
+	// Build container args
+	args := []string{
+		fmt.Sprintf("--pool-name=%s-pool", infScheduler.Name),
+		fmt.Sprintf("--pool-namespace=%s", infScheduler.Namespace),
+		fmt.Sprintf("--grpc-port=%d", grpcPort),
+		fmt.Sprintf("--grpc-health-port=%d", healthPort),
+		fmt.Sprintf("--metrics-port=%d", metricsPort),
+		"--config-file=/config/plugins.yaml",
+		fmt.Sprintf("--v=%d", getDefaultInt32(infScheduler.Spec.EndpointPicker.LogVerbosity, defaultEPPLogVerbosity)),
+	}
 
+	containerPorts := []corev1.ContainerPort{
+		{
+			ContainerPort: grpcPort,
+			Name:          "grpc",
+			Protocol:      corev1.ProtocolTCP,
+		},
+	}
+	if getDefaultBool(infScheduler.Spec.EndpointPicker.ExposeHealthPort, true) {
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			ContainerPort: healthPort,
+			Name:          "health",
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	if getDefaultBool(infScheduler.Spec.EndpointPicker.ExposeMetricsPort, true) {
+		containerPorts = append(containerPorts, corev1.ContainerPort{
+			ContainerPort: metricsPort,
+			Name:          "metrics",
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
+	var startupProbe, livenessProbe *corev1.Probe
+	if getDefaultBool(infScheduler.Spec.EndpointPicker.ExposeHealthPort, true) {
+		healthService := "inference-extension"
+		eppHealth := corev1.ProbeHandler{
+			GRPC: &corev1.GRPCAction{
+				Port:    healthPort,
+				Service: &healthService,
+			},
+		}
+		startupProbe = buildProbe(corev1.Probe{
+			ProbeHandler:     eppHealth,
+			PeriodSeconds:    2,
+			TimeoutSeconds:   1,
+			FailureThreshold: 15,
+		}, infScheduler.Spec.EndpointPicker.StartupProbe)
+		livenessProbe = buildProbe(corev1.Probe{
+			ProbeHandler:     eppHealth,
+			PeriodSeconds:    10,
+			TimeoutSeconds:   1,
+			FailureThreshold: 3,
+		}, infScheduler.Spec.EndpointPicker.LivenessProbe)
+	}
+
+	defaults := r.resourceDefaults().EndpointPicker
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-epp", infScheduler.Name),
-			Namespace: infScheduler.Namespace,
-			Labels:    labels,
+			Name:        fmt.Sprintf("%s-epp", infScheduler.Name),
+			Namespace:   infScheduler.Namespace,
+			Labels:      mergeStringMaps(infScheduler.Spec.EndpointPicker.DeploymentLabels, labels),
+			Annotations: mergeStringMaps(infScheduler.Spec.EndpointPicker.Annotations, infScheduler.Spec.EndpointPicker.DeploymentAnnotations),
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
+			Paused:   infScheduler.Spec.EndpointPicker.PauseRollout,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
@@ -286,34 +1340,15 @@ func (r *InferenceSchedulerReconciler) buildEPPDeployment(infScheduler *llmv1alp
 					ServiceAccountName: fmt.Sprintf("%s-epp", infScheduler.Name),
 					Containers: []corev1.Container{
 						{
-							Name:  "epp",
-							Image: image,
-							Args: []string{
-								fmt.Sprintf("--pool-name=%s-pool", infScheduler.Name),
-								fmt.Sprintf("--pool-namespace=%s", infScheduler.Namespace),
-								fmt.Sprintf("--grpc-port=%d", grpcPort),
-								"--grpc-health-port=9003",
-								"--config-file=/config/plugins.yaml",
-								"--v=2",
-							},
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: grpcPort,
-									Name:          "grpc",
-									Protocol:      corev1.ProtocolTCP,
-								},
-								{
-									ContainerPort: 9003,
-									Name:          "health",
-									Protocol:      corev1.ProtocolTCP,
-								},
-								{
-									ContainerPort: 9090,
-									Name:          "metrics",
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							Resources: infScheduler.Spec.EndpointPicker.Resources,
+							Name:          "epp",
+							Image:         image,
+							Args:          args,
+							Ports:         containerPorts,
+							StartupProbe:  startupProbe,
+							LivenessProbe: livenessProbe,
+							// Surfaces the end of the log, e.g. an unknown flag, in the pod status
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							Resources:                withDefaultResources(infScheduler.Spec.EndpointPicker.Resources, defaults.Requests, defaults.Limits),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "config",
@@ -324,14 +1359,8 @@ func (r *InferenceSchedulerReconciler) buildEPPDeployment(infScheduler *llmv1alp
 					},
 					Volumes: []corev1.Volume{
 						{
-							Name: "config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: fmt.Sprintf("%s-epp-config", infScheduler.Name),
-									},
-								},
-							},
+							Name:         "config",
+							VolumeSource: buildEPPConfigVolumeSource(infScheduler),
 						},
 					},
 				},
@@ -339,9 +1368,69 @@ func (r *InferenceSchedulerReconciler) buildEPPDeployment(infScheduler *llmv1alp
 		},
 	}
 
+	// Roll the EPP when the generated config changes. An overriding ConfigMap is not hashed
+	if configMap := r.buildEPPConfigMap(infScheduler); configMap != nil {
+		deployment.Spec.Template.Annotations = map[string]string{
+			configHashAnnotation: configMap.Annotations[configHashAnnotation],
+		}
+	}
+
+	deployment.Spec.Template.Spec.TopologySpreadConstraints = buildTopologySpreadConstraints(infScheduler.Spec.EndpointPicker.TopologySpreadConstraints, labels)
+	deployment.Spec.Template.Spec.ImagePullSecrets = imagePullSecrets(infScheduler, infScheduler.Spec.EndpointPicker.ImagePullSecrets)
+	// The EPP image runs as a non-root user and only reads its config
+	deployment.Spec.Template.Spec.SecurityContext = buildPodSecurityContext(infScheduler.Spec.EndpointPicker.PodSecurityContext, true)
+	deployment.Spec.Template.Spec.Containers[0].SecurityContext = buildContainerSecurityContext(infScheduler.Spec.EndpointPicker.SecurityContext, true)
+	deployment.Spec.Template.Spec.Containers[0].Env = buildEPPObservabilityEnv(infScheduler)
+
 	return deployment
 }
 
+// buildEPPConfigVolumeSource returns the source of the volume mounted at /config, the generated
+// ConfigMap or the key of the ConfigMap in PluginConfigMapRef projected as plugins.yaml
+func buildEPPConfigVolumeSource(infScheduler *llmv1alpha1.InferenceScheduler) corev1.VolumeSource {
+	ref := infScheduler.Spec.EndpointPicker.PluginConfigMapRef
+	if ref == nil {
+		return corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: fmt.Sprintf("%s-epp-config", infScheduler.Name),
+				},
+			},
+		}
+	}
+	return corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: ref.LocalObjectReference,
+			Items:                []corev1.KeyToPath{{Key: ref.Key, Path: "plugins.yaml"}},
+			Optional:             ref.Optional,
+		},
+	}
+}
+
+// buildEPPObservabilityEnv returns the OpenTelemetry environment of the EPP container, or nil if
+// no exporter is configured
+func buildEPPObservabilityEnv(infScheduler *llmv1alpha1.InferenceScheduler) []corev1.EnvVar {
+	observability := infScheduler.Spec.EndpointPicker.Observability
+	if observability == nil {
+		return nil
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "OTEL_SERVICE_NAME", Value: fmt.Sprintf("%s-epp", infScheduler.Name)},
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: observability.OTLPEndpoint},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: getDefaultString(observability.Protocol, "grpc")},
+	}
+	if observability.HeadersSecretRef != nil {
+		env = append(env, corev1.EnvVar{
+			Name: "OTEL_EXPORTER_OTLP_HEADERS",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: observability.HeadersSecretRef.DeepCopy(),
+			},
+		})
+	}
+	return env
+}
+
 // buildEPPService creates a Service for EPP (gRPC)
 func (r *InferenceSchedulerReconciler) buildEPPService(infScheduler *llmv1alpha1.InferenceScheduler) *corev1.Service {
 	labels := map[string]string{
@@ -349,36 +1438,59 @@ func (r *InferenceSchedulerReconciler) buildEPPService(infScheduler *llmv1alpha1
 	}
 
 	grpcPort := getDefaultInt32(&infScheduler.Spec.EndpointPicker.GRPCPort, defaultEPPGRPCPort)
+	healthPort := getDefaultInt32(infScheduler.Spec.EndpointPicker.HealthPort, defaultEPPHealthPort)
+	metricsPort := getDefaultInt32(infScheduler.Spec.EndpointPicker.MetricsPort, defaultEPPMetricsPort)
+
+	ports := []corev1.ServicePort{
+		{
+			Name:       "grpc",
+			Port:       grpcPort,
+			TargetPort: intstr.FromInt(int(grpcPort)),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+	if getDefaultBool(infScheduler.Spec.EndpointPicker.ExposeHealthPort, true) {
+		ports = append(ports, corev1.ServicePort{
+			Name:       "health",
+			Port:       healthPort,
+			TargetPort: intstr.FromInt(int(healthPort)),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+	if getDefaultBool(infScheduler.Spec.EndpointPicker.ExposeMetricsPort, true) {
+		ports = append(ports, corev1.ServicePort{
+			Name:       "metrics",
+			Port:       metricsPort,
+			TargetPort: intstr.FromInt(int(metricsPort)),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+
+	serviceType := corev1.ServiceTypeClusterIP
+	if nodePort := infScheduler.Spec.EndpointPicker.NodePort; nodePort != nil {
+		serviceType = corev1.ServiceTypeNodePort
+		for i := range ports {
+			switch {
+			case ports[i].Name == "grpc" && nodePort.GRPC != nil:
+				ports[i].NodePort = *nodePort.GRPC
+			case ports[i].Name == "health" && nodePort.Health != nil:
+				ports[i].NodePort = *nodePort.Health
+			}
+		}
+	}
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-epp", infScheduler.Name),
-			Namespace: infScheduler.Namespace,
-			Labels:    labels,
+			Name:        fmt.Sprintf("%s-epp", infScheduler.Name),
+			Namespace:   infScheduler.Namespace,
+			Labels:      mergeStringMaps(labels, map[string]string{instanceLabel: infScheduler.Name}),
+			Annotations: mergeStringMaps(infScheduler.Spec.EndpointPicker.Annotations),
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: labels,
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "grpc",
-					Port:       grpcPort,
-					TargetPort: intstr.FromInt(int(grpcPort)),
-					Protocol:   corev1.ProtocolTCP,
-				},
-				{
-					Name:       "health",
-					Port:       9003,
-					TargetPort: intstr.FromInt(9003),
-					Protocol:   corev1.ProtocolTCP,
-				},
-				{
-					Name:       "metrics",
-					Port:       9090,
-					TargetPort: intstr.FromInt(9090),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
-			Type: corev1.ServiceTypeClusterIP,
+			// Schedulers in one namespace must not reach or scrape each other's EPP
+			Selector: mergeStringMaps(r.buildEPPDeployment(infScheduler).Spec.Selector.MatchLabels),
+			Ports:    ports,
+			Type:     serviceType,
 		},
 	}
 
@@ -387,15 +1499,24 @@ func (r *InferenceSchedulerReconciler) buildEPPService(infScheduler *llmv1alpha1
 
 // buildInferencePool creates an InferencePool CR
 func (r *InferenceSchedulerReconciler) buildInferencePool(infScheduler *llmv1alpha1.InferenceScheduler) *unstructured.Unstructured {
-	modelName := sanitizeName(infScheduler.Spec.ModelServer.ModelName)
-
+	// Not the model, so the pods of the extra model servers join the pool too
 	labels := map[string]string{
-		"app":   "vllm",
-		"model": modelName,
+		"app":         "vllm",
+		instanceLabel: infScheduler.Name,
+	}
+
+	// Pin routing to a single ReplicaSet or StatefulSet revision if requested
+	if infScheduler.Spec.ModelServer.PinnedRevision != "" {
+		revisionLabel := appsv1.DefaultDeploymentUniqueLabelKey
+		if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+			revisionLabel = appsv1.ControllerRevisionHashLabelKey
+		}
+		labels[revisionLabel] = infScheduler.Spec.ModelServer.PinnedRevision
 	}
 
 	grpcPort := getDefaultInt32(&infScheduler.Spec.EndpointPicker.GRPCPort, defaultEPPGRPCPort)
 	modelServerPort := getDefaultInt32(&infScheduler.Spec.ModelServer.Port, defaultModelServerPort)
+	targetPort := getDefaultInt32(infScheduler.Spec.ModelServer.TargetPort, modelServerPort)
 
 	pool := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -411,13 +1532,14 @@ func (r *InferenceSchedulerReconciler) buildInferencePool(infScheduler *llmv1alp
 				},
 				"targetPorts": []interface{}{
 					map[string]interface{}{
-						"number": modelServerPort,
+						"number": targetPort,
 					},
 				},
+				// The v1 EndpointPickerRef has no timeout or retry fields, see EndpointPickerSpec
 				"endpointPickerRef": map[string]interface{}{
 					"name":        fmt.Sprintf("%s-epp", infScheduler.Name),
 					"port":        grpcPort,
-					"failureMode": "FailOpen",
+					"failureMode": getDefaultString(infScheduler.Spec.EndpointPicker.FailureMode, "FailOpen"),
 				},
 			},
 		},
@@ -431,6 +1553,54 @@ func (r *InferenceSchedulerReconciler) buildGateway(infScheduler *llmv1alpha1.In
 	className := getDefaultString(infScheduler.Spec.Gateway.ClassName, "kgateway")
 	listenerPort := getDefaultInt32(&infScheduler.Spec.Gateway.ListenerPort, defaultGatewayPort)
 
+	allowedNamespaces := map[string]interface{}{
+		"from": "Same",
+	}
+	if allowedRoutes := infScheduler.Spec.Gateway.AllowedRoutes; allowedRoutes != nil {
+		allowedNamespaces["from"] = getDefaultString(allowedRoutes.From, "Same")
+		if allowedRoutes.From == "Selector" && allowedRoutes.Selector != nil {
+			// A LabelSelector always converts, it only holds strings
+			selector, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(allowedRoutes.Selector)
+			allowedNamespaces["selector"] = selector
+		}
+	}
+
+	var listeners []interface{}
+	tls := infScheduler.Spec.Gateway.TLS
+	if tls == nil || tls.HTTPRedirect {
+		listeners = append(listeners, map[string]interface{}{
+			"name":     getDefaultString(infScheduler.Spec.Gateway.SectionName, "http"),
+			"protocol": "HTTP",
+			"port":     listenerPort,
+			"allowedRoutes": map[string]interface{}{
+				"namespaces": allowedNamespaces,
+			},
+		})
+	}
+	if tls != nil {
+		httpsListener := map[string]interface{}{
+			"name":     getDefaultString(tls.ListenerName, "https"),
+			"protocol": "HTTPS",
+			"port":     getDefaultInt32(&tls.Port, defaultGatewayTLSPort),
+			"tls": map[string]interface{}{
+				"mode": "Terminate",
+				"certificateRefs": []interface{}{
+					map[string]interface{}{
+						"kind": "Secret",
+						"name": tls.CertificateSecretName,
+					},
+				},
+			},
+			"allowedRoutes": map[string]interface{}{
+				"namespaces": allowedNamespaces,
+			},
+		}
+		if tls.Hostname != "" {
+			httpsListener["hostname"] = tls.Hostname
+		}
+		listeners = append(listeners, httpsListener)
+	}
+
 	gateway := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "gateway.networking.k8s.io/v1",
@@ -441,28 +1611,41 @@ func (r *InferenceSchedulerReconciler) buildGateway(infScheduler *llmv1alpha1.In
 			},
 			"spec": map[string]interface{}{
 				"gatewayClassName": className,
-				"listeners": []interface{}{
-					map[string]interface{}{
-						"name":     "http",
-						"protocol": "HTTP",
-						"port":     listenerPort,
-						"allowedRoutes": map[string]interface{}{
-							"namespaces": map[string]interface{}{
-								"from": "Same",
-							},
-						},
-					},
-				},
+				"listeners":        listeners,
 			},
 		},
 	}
+	if annotations := infScheduler.Spec.Gateway.Annotations; len(annotations) > 0 {
+		gateway.SetAnnotations(mergeStringMaps(annotations))
+	}
 
 	return gateway
 }
 
 // buildHTTPRoute creates an HTTPRoute resource
 func (r *InferenceSchedulerReconciler) buildHTTPRoute(infScheduler *llmv1alpha1.InferenceScheduler) *unstructured.Unstructured {
+	// The backend is the InferencePool, so default to the port the pool targets on its pods
 	modelServerPort := getDefaultInt32(&infScheduler.Spec.ModelServer.Port, defaultModelServerPort)
+	targetPort := getDefaultInt32(infScheduler.Spec.ModelServer.TargetPort, modelServerPort)
+
+	backendRef := map[string]interface{}{
+		"group": "inference.networking.k8s.io",
+		"kind":  "InferencePool",
+		"name":  fmt.Sprintf("%s-pool", infScheduler.Name),
+		"port":  targetPort,
+	}
+	if spec := infScheduler.Spec.Gateway.BackendRef; spec != nil {
+		backendRef["port"] = getDefaultInt32(spec.Port, targetPort)
+		if spec.Weight != nil {
+			backendRef["weight"] = *spec.Weight
+		}
+	}
+
+	// Gateway API requires a rule without backends or response filters to return 503
+	backendRefs := []interface{}{backendRef}
+	if infScheduler.Spec.MaintenanceMode {
+		backendRefs = []interface{}{}
+	}
 
 	httpRoute := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -472,29 +1655,163 @@ func (r *InferenceSchedulerReconciler) buildHTTPRoute(infScheduler *llmv1alpha1.
 				"name":      fmt.Sprintf("%s-route", infScheduler.Name),
 				"namespace": infScheduler.Namespace,
 			},
+			"spec": map[string]interface{}{
+				"parentRefs": []interface{}{
+					buildGatewayParentRef(infScheduler),
+				},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"matches":     buildRouteMatches(infScheduler),
+						"backendRefs": backendRefs,
+					},
+				},
+			},
+		},
+	}
+	setRouteHostnames(httpRoute, infScheduler)
+
+	return httpRoute
+}
+
+// buildRouteMatches creates the HTTPRoute matches for the configured paths, or the /v1/ prefix
+func buildRouteMatches(infScheduler *llmv1alpha1.InferenceScheduler) []interface{} {
+	var paths []llmv1alpha1.RoutePathMatch
+	if route := infScheduler.Spec.Gateway.Route; route != nil {
+		paths = route.Paths
+	}
+	if len(paths) == 0 {
+		paths = []llmv1alpha1.RoutePathMatch{{Type: "PathPrefix", Value: "/v1/"}}
+	}
+
+	matches := make([]interface{}, 0, len(paths))
+	for _, path := range paths {
+		matches = append(matches, map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":  getDefaultString(path.Type, "PathPrefix"),
+				"value": path.Value,
+			},
+		})
+	}
+	return matches
+}
+
+// setRouteHostnames sets the configured hostnames on an HTTPRoute. Without hostnames the route
+// matches every hostname of the listener
+func setRouteHostnames(httpRoute *unstructured.Unstructured, infScheduler *llmv1alpha1.InferenceScheduler) {
+	route := infScheduler.Spec.Gateway.Route
+	if route == nil || len(route.Hostnames) == 0 {
+		return
+	}
+	hostnames := make([]interface{}, 0, len(route.Hostnames))
+	for _, hostname := range route.Hostnames {
+		hostnames = append(hostnames, hostname)
+	}
+	httpRoute.Object["spec"].(map[string]interface{})["hostnames"] = hostnames
+}
+
+// gatewayKey returns the Gateway the HTTPRoutes attach to: the referenced existing Gateway, or
+// the one built by buildGateway
+func gatewayKey(infScheduler *llmv1alpha1.InferenceScheduler) types.NamespacedName {
+	if ref := infScheduler.Spec.Gateway.ExistingGatewayRef; ref != nil {
+		return types.NamespacedName{Namespace: getDefaultString(ref.Namespace, infScheduler.Namespace), Name: ref.Name}
+	}
+	return types.NamespacedName{Namespace: infScheduler.Namespace, Name: fmt.Sprintf("%s-gateway", infScheduler.Name)}
+}
+
+// buildGatewayParentRef creates the parentRefs entry that attaches an HTTPRoute to the Gateway
+func buildGatewayParentRef(infScheduler *llmv1alpha1.InferenceScheduler) map[string]interface{} {
+	key := gatewayKey(infScheduler)
+	parentRef := map[string]interface{}{
+		"name":      key.Name,
+		"namespace": key.Namespace,
+	}
+	if tls := infScheduler.Spec.Gateway.TLS; tls != nil {
+		// Only the redirect route may attach to the plain HTTP listener
+		parentRef["sectionName"] = getDefaultString(tls.ListenerName, "https")
+	} else if infScheduler.Spec.Gateway.SectionName != "" {
+		parentRef["sectionName"] = infScheduler.Spec.Gateway.SectionName
+	}
+	return parentRef
+}
+
+// buildHTTPSRedirectHTTPRoute creates an HTTPRoute on the HTTP listener that redirects to HTTPS
+func (r *InferenceSchedulerReconciler) buildHTTPSRedirectHTTPRoute(infScheduler *llmv1alpha1.InferenceScheduler) *unstructured.Unstructured {
+	tls := infScheduler.Spec.Gateway.TLS
+
+	redirect := map[string]interface{}{
+		"scheme":     "https",
+		"statusCode": int64(301),
+	}
+	// The default port of the scheme is left out of the Location header
+	if port := getDefaultInt32(&tls.Port, defaultGatewayTLSPort); port != defaultGatewayTLSPort {
+		redirect["port"] = port
+	}
+
+	httpRoute := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      fmt.Sprintf("%s-https-redirect", infScheduler.Name),
+				"namespace": infScheduler.Namespace,
+			},
 			"spec": map[string]interface{}{
 				"parentRefs": []interface{}{
 					map[string]interface{}{
-						"name":      fmt.Sprintf("%s-gateway", infScheduler.Name),
-						"namespace": infScheduler.Namespace,
+						"name":        fmt.Sprintf("%s-gateway", infScheduler.Name),
+						"namespace":   infScheduler.Namespace,
+						"sectionName": getDefaultString(infScheduler.Spec.Gateway.SectionName, "http"),
 					},
 				},
+				"rules": []interface{}{
+					map[string]interface{}{
+						"filters": []interface{}{
+							map[string]interface{}{
+								"type":            "RequestRedirect",
+								"requestRedirect": redirect,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	setRouteHostnames(httpRoute, infScheduler)
+
+	return httpRoute
+}
+
+// buildHealthCheckHTTPRoute creates an HTTPRoute that targets the model server Service directly on /health
+func (r *InferenceSchedulerReconciler) buildHealthCheckHTTPRoute(infScheduler *llmv1alpha1.InferenceScheduler) *unstructured.Unstructured {
+	modelServerPort := getDefaultInt32(&infScheduler.Spec.ModelServer.Port, defaultModelServerPort)
+
+	httpRoute := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      fmt.Sprintf("%s-health-route", infScheduler.Name),
+				"namespace": infScheduler.Namespace,
+			},
+			"spec": map[string]interface{}{
+				"parentRefs": []interface{}{
+					buildGatewayParentRef(infScheduler),
+				},
 				"rules": []interface{}{
 					map[string]interface{}{
 						"matches": []interface{}{
 							map[string]interface{}{
 								"path": map[string]interface{}{
-									"type":  "PathPrefix",
-									"value": "/v1/",
+									"type":  "Exact",
+									"value": "/health",
 								},
 							},
 						},
 						"backendRefs": []interface{}{
 							map[string]interface{}{
-								"group": "inference.networking.k8s.io",
-								"kind":  "InferencePool",
-								"name":  fmt.Sprintf("%s-pool", infScheduler.Name),
-								"port":  modelServerPort,
+								"kind": "Service",
+								"name": fmt.Sprintf("%s-vllm", infScheduler.Name),
+								"port": modelServerPort,
 							},
 						},
 					},
@@ -502,6 +1819,153 @@ func (r *InferenceSchedulerReconciler) buildHTTPRoute(infScheduler *llmv1alpha1.
 			},
 		},
 	}
+	setRouteHostnames(httpRoute, infScheduler)
 
 	return httpRoute
 }
+
+// buildPrometheusRule creates a PrometheusRule with the default alerts for an InferenceScheduler
+func (r *InferenceSchedulerReconciler) buildPrometheusRule(infScheduler *llmv1alpha1.InferenceScheduler) *unstructured.Unstructured {
+	namespace := infScheduler.Namespace
+	modelServerName := fmt.Sprintf("%s-vllm", infScheduler.Name)
+	eppName := fmt.Sprintf("%s-epp", infScheduler.Name)
+
+	modelServerReplicas := fmt.Sprintf(
+		`kube_deployment_status_replicas_available{namespace=%q,deployment=%q} < kube_deployment_spec_replicas{namespace=%q,deployment=%q}`,
+		namespace, modelServerName, namespace, modelServerName)
+	if infScheduler.Spec.ModelServer.Workload == workloadStatefulSet {
+		modelServerReplicas = fmt.Sprintf(
+			`kube_statefulset_status_replicas_ready{namespace=%q,statefulset=%q} < kube_statefulset_replicas{namespace=%q,statefulset=%q}`,
+			namespace, modelServerName, namespace, modelServerName)
+	}
+
+	alert := func(name, expr, duration, severity, summary string) interface{} {
+		return map[string]interface{}{
+			"alert": name,
+			"expr":  expr,
+			"for":   duration,
+			"labels": map[string]interface{}{
+				"severity": severity,
+			},
+			"annotations": map[string]interface{}{
+				"summary": summary,
+			},
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"name":      fmt.Sprintf("%s-alerts", infScheduler.Name),
+		"namespace": namespace,
+	}
+	if labels := monitoringLabels(infScheduler); labels != nil {
+		metadata["labels"] = labels
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "PrometheusRule",
+			"metadata":   metadata,
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name": fmt.Sprintf("%s.%s.inference-scheduler", namespace, infScheduler.Name),
+						"rules": []interface{}{
+							alert("InferenceSchedulerNotReady",
+								fmt.Sprintf(`inferencescheduler_ready{inferencescheduler_namespace=%q,inferencescheduler=%q} == 0`, namespace, infScheduler.Name),
+								"15m", "warning",
+								fmt.Sprintf("InferenceScheduler %s/%s has not been Ready for 15 minutes", namespace, infScheduler.Name)),
+							alert("InferenceSchedulerModelServerReplicasLow",
+								modelServerReplicas,
+								"10m", "warning",
+								fmt.Sprintf("Model server %s/%s has fewer ready replicas than desired", namespace, modelServerName)),
+							alert("InferenceSchedulerEPPDown",
+								fmt.Sprintf(`kube_deployment_status_replicas_available{namespace=%q,deployment=%q} == 0`, namespace, eppName),
+								"5m", "critical",
+								fmt.Sprintf("Endpoint picker %s/%s has no available replicas", namespace, eppName)),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// monitoringLabels returns the user's labels for the generated monitoring resources, or nil
+func monitoringLabels(infScheduler *llmv1alpha1.InferenceScheduler) map[string]interface{} {
+	var labels map[string]interface{}
+	if infScheduler.Spec.Monitoring != nil {
+		for k, v := range infScheduler.Spec.Monitoring.Labels {
+			if labels == nil {
+				labels = map[string]interface{}{}
+			}
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// buildServiceMonitor creates a ServiceMonitor for the EPP metrics port and, if requested, the
+// vLLM metrics of the model server Service. It returns nil if the ServiceMonitor is not enabled
+// or has nothing to scrape
+func (r *InferenceSchedulerReconciler) buildServiceMonitor(infScheduler *llmv1alpha1.InferenceScheduler) *unstructured.Unstructured {
+	monitoring := infScheduler.Spec.Monitoring
+	if monitoring == nil || !monitoring.ServiceMonitor {
+		return nil
+	}
+
+	// The endpoints name Service ports; each Service only has the port of its own endpoint
+	var apps, endpoints []interface{}
+	if getDefaultBool(infScheduler.Spec.EndpointPicker.ExposeMetricsPort, true) {
+		apps = append(apps, "epp")
+		endpoints = append(endpoints, map[string]interface{}{
+			"port": "metrics",
+			"path": "/metrics",
+		})
+	}
+	if monitoring.ScrapeModelServer {
+		portName := "http"
+		if spec := infScheduler.Spec.ModelServer.Service; spec != nil {
+			portName = getDefaultString(spec.PortName, portName)
+		}
+		apps = append(apps, "vllm")
+		endpoints = append(endpoints, map[string]interface{}{
+			"port": portName,
+			"path": "/metrics",
+		})
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	metadata := map[string]interface{}{
+		"name":      fmt.Sprintf("%s-metrics", infScheduler.Name),
+		"namespace": infScheduler.Namespace,
+	}
+	if labels := monitoringLabels(infScheduler); labels != nil {
+		metadata["labels"] = labels
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "ServiceMonitor",
+			"metadata":   metadata,
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						instanceLabel: infScheduler.Name,
+					},
+					"matchExpressions": []interface{}{
+						map[string]interface{}{
+							"key":      "app",
+							"operator": "In",
+							"values":   apps,
+						},
+					},
+				},
+				"endpoints": endpoints,
+			},
+		},
+	}
+}
@@ -0,0 +1,2333 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+	"github.com/aneeshkp/inference-scheduler-operator/internal/validation"
+)
+
+var _ = Describe("Resource builders", func() {
+	var infScheduler *llmv1alpha1.InferenceScheduler
+
+	BeforeEach(func() {
+		infScheduler = &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-scheduler",
+				Namespace: "default",
+			},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{
+					ModelName:         "Qwen/Qwen2.5-0.5B-Instruct",
+					HFTokenSecretName: "hf-token",
+				},
+			},
+		}
+	})
+
+	Context("When the model server has init and sidecar containers", func() {
+		initResources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		}
+		sidecarResources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("250m"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		}
+
+		BeforeEach(func() {
+			infScheduler.Spec.ModelServer.InitContainers = []corev1.Container{
+				{Name: "fetch-weights", Image: "busybox", Resources: *initResources.DeepCopy()},
+			}
+			infScheduler.Spec.ModelServer.Sidecars = []corev1.Container{
+				{Name: "proxy", Image: "envoyproxy/envoy", Resources: *sidecarResources.DeepCopy()},
+			}
+		})
+
+		It("should carry their resources through to the Deployment", func() {
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+
+			Expect(podSpec.InitContainers).To(HaveLen(1))
+			Expect(podSpec.InitContainers[0].Name).To(Equal("fetch-weights"))
+			Expect(podSpec.InitContainers[0].Resources.Requests).To(Equal(initResources.Requests))
+			Expect(podSpec.InitContainers[0].Resources.Limits).To(HaveKeyWithValue(corev1.ResourceMemory, resource.MustParse("128Mi")))
+
+			Expect(podSpec.Containers).To(HaveLen(2))
+			Expect(podSpec.Containers[0].Name).To(Equal("vllm"))
+			Expect(podSpec.Containers[1].Name).To(Equal("proxy"))
+			Expect(podSpec.Containers[1].Resources.Requests).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("250m")))
+			Expect(podSpec.Containers[1].Resources.Limits).To(Equal(sidecarResources.Limits))
+		})
+
+		It("should not share resource lists with the spec", func() {
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+
+			podSpec.Containers[1].Resources.Limits[corev1.ResourceCPU] = resource.MustParse("1")
+			Expect(infScheduler.Spec.ModelServer.Sidecars[0].Resources.Limits.Cpu().String()).To(Equal("500m"))
+		})
+
+		It("should not warn about sidecars without GPUs", func() {
+			Expect(validateSidecars(infScheduler.Spec.ModelServer.Sidecars)).To(BeEmpty())
+		})
+
+		It("should warn about sidecars requesting GPUs", func() {
+			infScheduler.Spec.ModelServer.Sidecars[0].Resources.Limits[gpuResourceName] = resource.MustParse("1")
+
+			warnings := validateSidecars(infScheduler.Spec.ModelServer.Sidecars)
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring(`"proxy"`))
+		})
+	})
+
+	Context("When the EPP health and metrics ports are disabled", func() {
+		It("should omit them from the EPP Deployment and Service", func() {
+			disabled := false
+			infScheduler.Spec.EndpointPicker.ExposeHealthPort = &disabled
+			infScheduler.Spec.EndpointPicker.ExposeMetricsPort = &disabled
+
+			r := &InferenceSchedulerReconciler{}
+			containerPorts := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Ports
+			Expect(containerPorts).To(HaveLen(1))
+			Expect(containerPorts[0].Name).To(Equal("grpc"))
+
+			servicePorts := r.buildEPPService(infScheduler).Spec.Ports
+			Expect(servicePorts).To(HaveLen(1))
+			Expect(servicePorts[0].Name).To(Equal("grpc"))
+		})
+
+		It("should expose all ports by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Ports).To(HaveLen(3))
+			Expect(r.buildEPPService(infScheduler).Spec.Ports).To(HaveLen(3))
+		})
+
+		It("should pass the configured ports and verbosity to the EPP", func() {
+			healthPort, metricsPort, verbosity := int32(9103), int32(9190), int32(4)
+			infScheduler.Spec.EndpointPicker.HealthPort = &healthPort
+			infScheduler.Spec.EndpointPicker.MetricsPort = &metricsPort
+			infScheduler.Spec.EndpointPicker.LogVerbosity = &verbosity
+
+			r := &InferenceSchedulerReconciler{}
+			container := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(container.Args).To(ContainElements("--grpc-health-port=9103", "--metrics-port=9190", "--v=4"))
+			Expect(container.StartupProbe.GRPC.Port).To(Equal(healthPort))
+			containerPorts := map[string]int32{}
+			for _, port := range container.Ports {
+				containerPorts[port.Name] = port.ContainerPort
+			}
+			Expect(containerPorts).To(HaveKeyWithValue("health", healthPort))
+			Expect(containerPorts).To(HaveKeyWithValue("metrics", metricsPort))
+
+			for _, port := range r.buildEPPService(infScheduler).Spec.Ports {
+				Expect(port.TargetPort.IntValue()).To(BeEquivalentTo(port.Port))
+				if port.Name == "metrics" {
+					Expect(port.Port).To(Equal(metricsPort))
+				}
+			}
+		})
+
+		It("should only pass flags the EPP defines", func() {
+			infScheduler.Spec.EndpointPicker.GRPCPort = 9002
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+				"--pool-name=test-scheduler-pool",
+				"--pool-namespace=default",
+				"--grpc-port=9002",
+				"--grpc-health-port=9003",
+				"--metrics-port=9090",
+				"--config-file=/config/plugins.yaml",
+				"--v=2",
+			}))
+		})
+
+		It("should reject EPP ports used twice", func() {
+			infScheduler.Spec.EndpointPicker.GRPCPort = 9002
+			Expect(validateEPPPorts(infScheduler.Spec.EndpointPicker)).To(BeEmpty())
+
+			healthPort := int32(9002)
+			infScheduler.Spec.EndpointPicker.HealthPort = &healthPort
+			Expect(validateEPPPorts(infScheduler.Spec.EndpointPicker)).To(ConsistOf(ContainSubstring("gRPC and health")))
+		})
+	})
+
+	Context("When scorer parameters are configured", func() {
+		It("should prefer typed parameters and pass through unknown ones", func() {
+			queueThreshold := int32(64)
+			infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer = &llmv1alpha1.ScorerPlugin{
+				Enabled:        true,
+				QueueThreshold: &queueThreshold,
+				Parameters: map[string]string{
+					"queueThreshold": "256",
+					"customKey":      "value",
+				},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).To(ContainSubstring(`queueThreshold: "64"`))
+			Expect(config).To(ContainSubstring(`customKey: "value"`))
+			Expect(config).NotTo(ContainSubstring("256"))
+		})
+
+		It("should fall back to the parameters map and defaults", func() {
+			infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer = &llmv1alpha1.ScorerPlugin{Enabled: true}
+			infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer = &llmv1alpha1.ScorerPlugin{
+				Enabled:    true,
+				Parameters: map[string]string{"cacheHitBonus": "0.5"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).To(ContainSubstring(`queueThreshold: "128"`))
+			Expect(config).To(ContainSubstring(`cacheHitBonus: "0.5"`))
+		})
+
+		It("should render the session and LoRA affinity scorers", func() {
+			weight := 3.0
+			infScheduler.Spec.EndpointPicker.Plugins.SessionAffinityScorer = &llmv1alpha1.ScorerPlugin{Enabled: true}
+			infScheduler.Spec.EndpointPicker.Plugins.LoraAffinityScorer = &llmv1alpha1.ScorerPlugin{
+				Enabled:    true,
+				Weight:     &weight,
+				Parameters: map[string]string{"maxAdapters": "4"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).To(ContainSubstring("\n  - type: session-affinity-scorer\n    weight: 1.0"))
+			Expect(config).To(ContainSubstring("\n  - type: lora-affinity-scorer\n    weight: 3.0\n    parameters:\n      maxAdapters: \"4\""))
+		})
+
+		It("should skip disabled affinity scorers", func() {
+			infScheduler.Spec.EndpointPicker.Plugins.SessionAffinityScorer = &llmv1alpha1.ScorerPlugin{Enabled: false}
+
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).NotTo(ContainSubstring("session-affinity-scorer"))
+		})
+
+		It("should render additional scorers in order after the typed ones", func() {
+			infScheduler.Spec.EndpointPicker.Plugins.SessionAffinityScorer = &llmv1alpha1.ScorerPlugin{Enabled: true}
+			infScheduler.Spec.EndpointPicker.Plugins.AdditionalScorers = []llmv1alpha1.AdditionalScorerPlugin{
+				{Type: "queue-scorer", Parameters: map[string]string{"maxQueue": "10"}},
+				{Type: "running-requests-scorer"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).To(ContainSubstring("\n  - type: queue-scorer\n    weight: 1.0\n    parameters:\n      maxQueue: \"10\""))
+			Expect(config).To(ContainSubstring("\n  - type: running-requests-scorer\n    weight: 1.0"))
+			Expect(strings.Index(config, "session-affinity-scorer")).To(BeNumerically("<", strings.Index(config, "queue-scorer")))
+			Expect(strings.Index(config, "queue-scorer")).To(BeNumerically("<", strings.Index(config, "running-requests-scorer")))
+		})
+	})
+
+	Context("When two schedulers serve the same model in one namespace", func() {
+		It("should give each Service and InferencePool a selector matching only its own pods", func() {
+			other := infScheduler.DeepCopy()
+			other.Name = "other-scheduler"
+
+			r := &InferenceSchedulerReconciler{}
+			for _, pair := range [][2]*llmv1alpha1.InferenceScheduler{{infScheduler, other}, {other, infScheduler}} {
+				own := labels.Set(r.buildModelServerDeployment(pair[0]).Spec.Template.Labels)
+				foreign := labels.Set(r.buildModelServerDeployment(pair[1]).Spec.Template.Labels)
+
+				serviceSelector := labels.SelectorFromSet(r.buildModelServerService(pair[0]).Spec.Selector)
+				Expect(serviceSelector.Matches(own)).To(BeTrue())
+				Expect(serviceSelector.Matches(foreign)).To(BeFalse())
+
+				matchLabels, _, _ := unstructured.NestedFieldNoCopy(r.buildInferencePool(pair[0]).Object, "spec", "selector", "matchLabels")
+				poolSelector := labels.SelectorFromSet(matchLabels.(map[string]string))
+				Expect(poolSelector.Matches(own)).To(BeTrue())
+				Expect(poolSelector.Matches(foreign)).To(BeFalse())
+			}
+		})
+
+		It("should give each EPP Service a selector matching only its own EPP pods", func() {
+			other := infScheduler.DeepCopy()
+			other.Name = "other-scheduler"
+
+			r := &InferenceSchedulerReconciler{}
+			for _, pair := range [][2]*llmv1alpha1.InferenceScheduler{{infScheduler, other}, {other, infScheduler}} {
+				own := labels.Set(r.buildEPPDeployment(pair[0]).Spec.Template.Labels)
+				foreign := labels.Set(r.buildEPPDeployment(pair[1]).Spec.Template.Labels)
+
+				serviceSelector := labels.SelectorFromSet(r.buildEPPService(pair[0]).Spec.Selector)
+				Expect(serviceSelector.Matches(own)).To(BeTrue())
+				Expect(serviceSelector.Matches(foreign)).To(BeFalse())
+			}
+		})
+
+		It("should not let pod labels override the instance label", func() {
+			infScheduler.Spec.ModelServer.Labels = map[string]string{instanceLabel: "other-scheduler"}
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Labels).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+		})
+	})
+
+	Context("When validating the model name", func() {
+		DescribeTable("should accept HuggingFace repo ids and absolute paths",
+			func(name string) {
+				Expect(validateModelName(name)).To(Succeed())
+			},
+			Entry("org/model", "Qwen/Qwen2.5-0.5B-Instruct"),
+			Entry("underscores and dots", "meta-llama/Llama_3.1-8B.v2"),
+			Entry("legacy id without an owner", "gpt2"),
+			Entry("local path", "/models/qwen2.5-0.5b"),
+		)
+
+		DescribeTable("should reject names vLLM cannot resolve",
+			func(name, message string) {
+				Expect(validateModelName(name)).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("empty", "", "empty"),
+			Entry("only whitespace", "   ", "empty"),
+			Entry("trailing whitespace", "Qwen/Qwen2.5-0.5B-Instruct ", "whitespace"),
+			Entry("invalid character", "Qwen/Qwen2.5:0.5B", "not a valid HuggingFace repo id"),
+			Entry("leading dash", "-Qwen/model", "not a valid HuggingFace repo id"),
+			Entry("double dot", "Qwen/model..v2", "not a valid HuggingFace repo id"),
+			Entry("empty owner", "/", "root directory"),
+			Entry("missing name", "Qwen/", "not a valid HuggingFace repo id"),
+			Entry("too many parts", "org/team/model", "org/model"),
+		)
+
+		It("should report every model server with an invalid name", func() {
+			infScheduler.Spec.ModelServer.ModelName = "Qwen/Qwen 2.5"
+			infScheduler.Spec.ExtraModelServers = []llmv1alpha1.ExtraModelServerSpec{
+				{Name: "variant-b", ModelName: "Qwen/Qwen2.5-1.5B-Instruct"},
+				{Name: "variant-c", ModelName: "Qwen/Qwen2.5?"},
+			}
+			Expect(validateModelNames(infScheduler.Spec)).To(ConsistOf(
+				HavePrefix("modelServer: "),
+				HavePrefix("extra model server variant-c: "),
+			))
+		})
+	})
+
+	Context("When extra model servers are configured", func() {
+		var extra llmv1alpha1.ExtraModelServerSpec
+
+		BeforeEach(func() {
+			replicas := int32(2)
+			extra = llmv1alpha1.ExtraModelServerSpec{
+				Name:      "variant-b",
+				ModelName: "Qwen/Qwen2.5-1.5B-Instruct",
+				Replicas:  &replicas,
+				Image:     "vllm/vllm-openai:v0.11.0",
+			}
+			infScheduler.Spec.ExtraModelServers = []llmv1alpha1.ExtraModelServerSpec{extra}
+		})
+
+		It("should let both backends join one pool without selecting each other's pods", func() {
+			r := &InferenceSchedulerReconciler{}
+			primary := r.buildModelServerDeployment(infScheduler)
+			objects := r.buildExtraModelServerObjects(infScheduler, extra)
+			Expect(objects).To(HaveLen(2))
+			variant := objects[0].(*appsv1.Deployment)
+			variantService := objects[1].(*corev1.Service)
+			Expect(variant.Name).To(Equal("test-scheduler-variant-b-vllm"))
+			Expect(variantService.Name).To(Equal("test-scheduler-variant-b-vllm"))
+
+			primaryPods := labels.Set(primary.Spec.Template.Labels)
+			variantPods := labels.Set(variant.Spec.Template.Labels)
+			Expect(variantPods).To(HaveKeyWithValue(modelServerLabel, "variant-b"))
+
+			matchLabels, _, _ := unstructured.NestedFieldNoCopy(r.buildInferencePool(infScheduler).Object, "spec", "selector", "matchLabels")
+			poolSelector := labels.SelectorFromSet(matchLabels.(map[string]string))
+			Expect(poolSelector.Matches(primaryPods)).To(BeTrue())
+			Expect(poolSelector.Matches(variantPods)).To(BeTrue())
+
+			Expect(labels.SelectorFromSet(primary.Spec.Selector.MatchLabels).Matches(variantPods)).To(BeFalse())
+			Expect(labels.SelectorFromSet(variant.Spec.Selector.MatchLabels).Matches(primaryPods)).To(BeFalse())
+			Expect(labels.SelectorFromSet(r.buildModelServerService(infScheduler).Spec.Selector).Matches(variantPods)).To(BeFalse())
+			Expect(labels.SelectorFromSet(variantService.Spec.Selector).Matches(variantPods)).To(BeTrue())
+			Expect(labels.SelectorFromSet(variantService.Spec.Selector).Matches(primaryPods)).To(BeFalse())
+		})
+
+		It("should take the settings it does not override from the primary model server", func() {
+			infScheduler.Spec.ModelServer.ExtraArgs = []string{"--max-model-len=4096"}
+			infScheduler.Spec.ModelServer.Autoscaling = &llmv1alpha1.AutoscalingSpec{MaxReplicas: 4}
+
+			r := &InferenceSchedulerReconciler{}
+			variant := r.buildExtraModelServerObjects(infScheduler, extra)[0].(*appsv1.Deployment)
+			container := variant.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("vllm/vllm-openai:v0.11.0"))
+			Expect(container.Args).To(ContainElements("--model=Qwen/Qwen2.5-1.5B-Instruct", "--max-model-len=4096"))
+			// Autoscaling only applies to the primary model server
+			Expect(*variant.Spec.Replicas).To(Equal(int32(2)))
+			Expect(infScheduler.Spec.ModelServer.ModelName).To(Equal("Qwen/Qwen2.5-0.5B-Instruct"))
+		})
+
+		It("should give a StatefulSet backend its own headless Service", func() {
+			infScheduler.Spec.ModelServer.Workload = workloadStatefulSet
+
+			r := &InferenceSchedulerReconciler{}
+			objects := r.buildExtraModelServerObjects(infScheduler, extra)
+			Expect(objects).To(HaveLen(3))
+			Expect(objects[0].(*appsv1.StatefulSet).Spec.ServiceName).To(Equal("test-scheduler-variant-b-vllm-headless"))
+			Expect(objects[2].GetName()).To(Equal("test-scheduler-variant-b-vllm-headless"))
+			Expect(objects[2].(*corev1.Service).Spec.Selector).To(HaveKeyWithValue(modelServerLabel, "variant-b"))
+		})
+
+		It("should reject backends that would select each other's pods or leave the pool", func() {
+			Expect(validateExtraModelServers(infScheduler.Spec)).To(BeEmpty())
+
+			infScheduler.Spec.ExtraModelServers = append(infScheduler.Spec.ExtraModelServers,
+				llmv1alpha1.ExtraModelServerSpec{Name: "variant-c", ModelName: infScheduler.Spec.ModelServer.ModelName})
+			infScheduler.Spec.ModelServer.PinnedRevision = "abc123"
+			Expect(validateExtraModelServers(infScheduler.Spec)).To(ConsistOf(
+				ContainSubstring("serves model Qwen/Qwen2.5-0.5B-Instruct, which modelServer already serves"),
+				ContainSubstring("pinnedRevision"),
+			))
+		})
+	})
+
+	Context("When a model server revision is pinned", func() {
+		poolSelector := func(pool *unstructured.Unstructured) map[string]string {
+			matchLabels, _, _ := unstructured.NestedFieldNoCopy(pool.Object, "spec", "selector", "matchLabels")
+			return matchLabels.(map[string]string)
+		}
+
+		It("should select all model server pods by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(poolSelector(r.buildInferencePool(infScheduler))).To(Equal(map[string]string{
+				"app":         "vllm",
+				instanceLabel: "test-scheduler",
+			}))
+		})
+
+		It("should add the pod-template-hash to the pool selector only", func() {
+			infScheduler.Spec.ModelServer.PinnedRevision = "5d8f7c9b6"
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(poolSelector(r.buildInferencePool(infScheduler))).To(HaveKeyWithValue(appsv1.DefaultDeploymentUniqueLabelKey, "5d8f7c9b6"))
+
+			// The Deployment controller stamps the hash on the pods itself, and the selector is immutable
+			deployment := r.buildModelServerDeployment(infScheduler)
+			Expect(deployment.Spec.Selector.MatchLabels).NotTo(HaveKey(appsv1.DefaultDeploymentUniqueLabelKey))
+			Expect(deployment.Spec.Template.Labels).NotTo(HaveKey(appsv1.DefaultDeploymentUniqueLabelKey))
+			Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue("app", "vllm"))
+			Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+		})
+
+		It("should match the controller-revision-hash of a StatefulSet", func() {
+			infScheduler.Spec.ModelServer.Workload = workloadStatefulSet
+			infScheduler.Spec.ModelServer.PinnedRevision = "test-scheduler-vllm-7c9d5f"
+
+			r := &InferenceSchedulerReconciler{}
+			selector := poolSelector(r.buildInferencePool(infScheduler))
+			Expect(selector).To(HaveKeyWithValue(appsv1.ControllerRevisionHashLabelKey, "test-scheduler-vllm-7c9d5f"))
+			Expect(selector).NotTo(HaveKey(appsv1.DefaultDeploymentUniqueLabelKey))
+
+			statefulSet := r.buildModelServerWorkload(infScheduler).(*appsv1.StatefulSet)
+			Expect(statefulSet.Spec.Selector.MatchLabels).NotTo(HaveKey(appsv1.ControllerRevisionHashLabelKey))
+			Expect(statefulSet.Spec.Template.Labels).NotTo(HaveKey(appsv1.ControllerRevisionHashLabelKey))
+		})
+	})
+
+	Context("When configuring the HuggingFace token", func() {
+		It("should read HF_TOKEN from an optional secret reference", func() {
+			r := &InferenceSchedulerReconciler{}
+			env := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Env
+			Expect(env[0].Name).To(Equal("HF_TOKEN"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Name).To(Equal("hf-token"))
+			Expect(env[0].ValueFrom.SecretKeyRef.Key).To(Equal("token"))
+			Expect(*env[0].ValueFrom.SecretKeyRef.Optional).To(BeTrue())
+		})
+
+		It("should not set HF_TOKEN without a token secret", func() {
+			infScheduler.Spec.ModelServer.HFTokenSecretName = ""
+			infScheduler.Spec.ModelServer.PreloadModel = &llmv1alpha1.PreloadModelSpec{}
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Containers[0].Env).NotTo(ContainElement(HaveField("Name", "HF_TOKEN")))
+			for _, container := range podSpec.InitContainers {
+				Expect(container.Env).NotTo(ContainElement(HaveField("Name", "HF_TOKEN")))
+			}
+		})
+	})
+
+	Context("When preloading the model", func() {
+		It("should download into an emptyDir shared with vLLM when no cache is configured", func() {
+			infScheduler.Spec.ModelServer.PreloadModel = &llmv1alpha1.PreloadModelSpec{}
+			infScheduler.Spec.ModelServer.InitContainers = []corev1.Container{{Name: "fix-permissions", Image: "busybox"}}
+			infScheduler.Spec.ModelServer.ExtraEnv = []corev1.EnvVar{{Name: "HF_ENDPOINT", Value: "https://hf-mirror.example.com"}}
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+				Name:         "model-cache",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}))
+			Expect(podSpec.InitContainers).To(HaveLen(2))
+			Expect(podSpec.InitContainers[0].Name).To(Equal("fix-permissions"))
+
+			preload := podSpec.InitContainers[1]
+			vllm := podSpec.Containers[0]
+			Expect(preload.Name).To(Equal("preload-model"))
+			Expect(preload.Image).To(Equal(vllm.Image))
+			Expect(preload.Command).To(Equal([]string{"huggingface-cli"}))
+			Expect(preload.Args).To(Equal([]string{"download", "Qwen/Qwen2.5-0.5B-Instruct"}))
+			Expect(preload.VolumeMounts).To(ConsistOf(
+				corev1.VolumeMount{Name: "model-cache", MountPath: "/root/.cache/huggingface"},
+				corev1.VolumeMount{Name: "tmp", MountPath: "/tmp"},
+				corev1.VolumeMount{Name: "home", MountPath: "/home/vllm"},
+			))
+			Expect(vllm.VolumeMounts).To(ContainElements(preload.VolumeMounts))
+			Expect(preload.Env).To(ContainElements(
+				HaveField("Name", "HF_TOKEN"),
+				corev1.EnvVar{Name: "HF_HOME", Value: "/root/.cache/huggingface"},
+				corev1.EnvVar{Name: "HF_ENDPOINT", Value: "https://hf-mirror.example.com"},
+			))
+			Expect(preload.Env[0].ValueFrom.SecretKeyRef.Name).To(Equal(infScheduler.Spec.ModelServer.HFTokenSecretName))
+		})
+
+		It("should download into the configured model cache and download directory", func() {
+			infScheduler.Spec.ModelServer.PreloadModel = &llmv1alpha1.PreloadModelSpec{Image: "example.com/hf-downloader:1.0"}
+			infScheduler.Spec.ModelServer.DownloadDir = "/models/weights"
+			infScheduler.Spec.ModelServer.ModelCache = &llmv1alpha1.ModelCacheSpec{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "models"},
+				MountPath:             "/models",
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			preload := podSpec.InitContainers[0]
+			Expect(preload.Image).To(Equal("example.com/hf-downloader:1.0"))
+			Expect(preload.Args).To(ContainElement("--cache-dir=/models/weights"))
+			Expect(preload.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "model-cache", MountPath: "/models"}))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("VolumeSource.PersistentVolumeClaim.ClaimName", "models")))
+		})
+
+		It("should default the requests the user leaves unset", func() {
+			infScheduler.Spec.ModelServer.PreloadModel = &llmv1alpha1.PreloadModelSpec{}
+			r := &InferenceSchedulerReconciler{}
+			preload := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.InitContainers[0]
+			Expect(preload.Resources.Requests).To(Equal(DefaultResources().ModelServer.Requests))
+			Expect(preload.Resources.Limits).To(BeEmpty())
+
+			infScheduler.Spec.ModelServer.PreloadModel.Resources = corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+			}
+			preload = r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.InitContainers[0]
+			Expect(preload.Resources.Limits[corev1.ResourceMemory]).To(Equal(resource.MustParse("8Gi")))
+			Expect(preload.Resources.Limits).NotTo(HaveKey(corev1.ResourceCPU))
+			Expect(preload.Resources.Requests[corev1.ResourceMemory]).To(Equal(DefaultResources().ModelServer.Requests[corev1.ResourceMemory]))
+		})
+	})
+
+	Context("When building security contexts", func() {
+		It("should default both components to settings the restricted Pod Security Standard accepts", func() {
+			r := &InferenceSchedulerReconciler{}
+			epp := r.buildEPPDeployment(infScheduler).Spec.Template.Spec
+			Expect(*epp.SecurityContext.RunAsNonRoot).To(BeTrue())
+			Expect(epp.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+			Expect(*epp.Containers[0].SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(epp.Containers[0].SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+			Expect(*epp.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(*vllm.SecurityContext.RunAsNonRoot).To(BeTrue())
+			Expect(*vllm.SecurityContext.RunAsUser).NotTo(BeZero())
+			Expect(*vllm.SecurityContext.RunAsGroup).NotTo(BeZero())
+			Expect(*vllm.SecurityContext.FSGroup).To(Equal(*vllm.SecurityContext.RunAsGroup))
+			Expect(vllm.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+			Expect(*vllm.Containers[0].SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(vllm.Containers[0].SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+			Expect(*vllm.Containers[0].SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+			Expect(vllm.Volumes).To(ContainElement(HaveField("Name", "home")))
+		})
+
+		It("should pass the restricted Pod Security Standard checks for a default spec", func() {
+			infScheduler.Spec.ModelServer.PreloadModel = &llmv1alpha1.PreloadModelSpec{}
+
+			r := &InferenceSchedulerReconciler{}
+			for _, podSpec := range []corev1.PodSpec{
+				r.buildModelServerDeployment(infScheduler).Spec.Template.Spec,
+				r.buildEPPDeployment(infScheduler).Spec.Template.Spec,
+			} {
+				Expect(*podSpec.SecurityContext.RunAsNonRoot).To(BeTrue())
+				if podSpec.SecurityContext.RunAsUser != nil {
+					Expect(*podSpec.SecurityContext.RunAsUser).NotTo(BeZero())
+				}
+				Expect(podSpec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+				for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+					Expect(*container.SecurityContext.AllowPrivilegeEscalation).To(BeFalse(), container.Name)
+					Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")), container.Name)
+					Expect(container.SecurityContext.Privileged).To(BeNil(), container.Name)
+				}
+			}
+		})
+
+		It("should apply the configured model server security contexts and keep fsGroup", func() {
+			runAsNonRoot, readOnly := true, true
+			uid, fsGroup := int64(1000), int64(2000)
+			infScheduler.Spec.ModelServer.FSGroup = &fsGroup
+			infScheduler.Spec.ModelServer.PodSecurityContext = &corev1.PodSecurityContext{RunAsNonRoot: &runAsNonRoot, RunAsUser: &uid}
+			infScheduler.Spec.ModelServer.SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnly}
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(*podSpec.SecurityContext.RunAsUser).To(Equal(int64(1000)))
+			Expect(*podSpec.SecurityContext.FSGroup).To(Equal(int64(2000)))
+			Expect(podSpec.SecurityContext.SeccompProfile).To(BeNil())
+			Expect(podSpec.Containers[0].SecurityContext).To(Equal(&corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnly}))
+			Expect(infScheduler.Spec.ModelServer.PodSecurityContext.FSGroup).To(BeNil())
+		})
+
+		It("should add writable volumes when the vLLM root filesystem is read-only", func() {
+			readOnly := true
+			infScheduler.Spec.ModelServer.SecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnly}
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Volumes).To(ContainElements(
+				corev1.Volume{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				corev1.Volume{Name: "home", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			))
+			Expect(podSpec.Containers[0].VolumeMounts).To(ContainElements(
+				corev1.VolumeMount{Name: "tmp", MountPath: "/tmp"},
+				corev1.VolumeMount{Name: "home", MountPath: "/home/vllm"},
+			))
+			Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "HOME", Value: "/home/vllm"}))
+		})
+	})
+
+	Context("When configuring model server shutdown", func() {
+		It("should default the grace period without a preStop hook", func() {
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(*podSpec.TerminationGracePeriodSeconds).To(Equal(int64(60)))
+			Expect(podSpec.Containers[0].Lifecycle).To(BeNil())
+		})
+
+		It("should set the configured grace period and preStop sleep", func() {
+			gracePeriod := int64(120)
+			sleep := int32(15)
+			infScheduler.Spec.ModelServer.TerminationGracePeriodSeconds = &gracePeriod
+			infScheduler.Spec.ModelServer.PreStopSleepSeconds = &sleep
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerStatefulSet(infScheduler).Spec.Template.Spec
+			Expect(*podSpec.TerminationGracePeriodSeconds).To(Equal(int64(120)))
+			Expect(podSpec.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{"sleep", "15"}))
+			Expect(validateModelServerShutdown(infScheduler.Spec.ModelServer)).To(Succeed())
+		})
+
+		It("should reject a preStop sleep that outlasts the grace period", func() {
+			sleep := int32(60)
+			infScheduler.Spec.ModelServer.PreStopSleepSeconds = &sleep
+			Expect(validateModelServerShutdown(infScheduler.Spec.ModelServer)).To(MatchError(ContainSubstring("must be less than")))
+		})
+	})
+
+	Context("When building probes", func() {
+		It("should give each component its own default probes", func() {
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			epp := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+
+			Expect(vllm.StartupProbe).NotTo(BeNil())
+			Expect(vllm.StartupProbe.HTTPGet).NotTo(BeNil())
+			Expect(vllm.StartupProbe.HTTPGet.Path).To(Equal("/health"))
+			Expect(vllm.LivenessProbe.HTTPGet).NotTo(BeNil())
+			Expect(vllm.ReadinessProbe).NotTo(BeNil())
+			Expect(vllm.ReadinessProbe.HTTPGet.Path).To(Equal("/health"))
+			Expect(vllm.ReadinessProbe.HTTPGet.Port.IntValue()).To(BeEquivalentTo(vllm.Ports[0].ContainerPort))
+
+			Expect(epp.StartupProbe).NotTo(BeNil())
+			Expect(epp.StartupProbe.GRPC).NotTo(BeNil())
+			Expect(epp.StartupProbe.GRPC.Port).To(Equal(int32(9003)))
+			Expect(epp.LivenessProbe.GRPC).NotTo(BeNil())
+
+			// vLLM loads model weights, so it is given far longer to start than the EPP
+			vllmStartup := vllm.StartupProbe.InitialDelaySeconds + vllm.StartupProbe.PeriodSeconds*vllm.StartupProbe.FailureThreshold
+			eppStartup := epp.StartupProbe.InitialDelaySeconds + epp.StartupProbe.PeriodSeconds*epp.StartupProbe.FailureThreshold
+			Expect(vllmStartup).To(BeNumerically(">", eppStartup))
+		})
+
+		It("should apply overrides only to the configured component", func() {
+			failureThreshold := int32(200)
+			infScheduler.Spec.ModelServer.StartupProbe = &llmv1alpha1.ProbeSpec{FailureThreshold: &failureThreshold}
+			infScheduler.Spec.EndpointPicker.LivenessProbe = &llmv1alpha1.ProbeSpec{Disabled: true}
+
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			epp := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+
+			Expect(vllm.StartupProbe.FailureThreshold).To(Equal(int32(200)))
+			Expect(vllm.StartupProbe.PeriodSeconds).To(Equal(int32(10)))
+			Expect(vllm.LivenessProbe).NotTo(BeNil())
+			Expect(epp.StartupProbe.FailureThreshold).To(Equal(int32(15)))
+			Expect(epp.LivenessProbe).To(BeNil())
+		})
+
+		It("should allow the model server readiness probe to be tuned or disabled", func() {
+			periodSeconds := int32(15)
+			infScheduler.Spec.ModelServer.ReadinessProbe = &llmv1alpha1.ProbeSpec{PeriodSeconds: &periodSeconds}
+
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.ReadinessProbe.PeriodSeconds).To(Equal(int32(15)))
+			Expect(vllm.ReadinessProbe.FailureThreshold).To(Equal(int32(3)))
+
+			infScheduler.Spec.ModelServer.ReadinessProbe = &llmv1alpha1.ProbeSpec{Disabled: true}
+			vllm = r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.ReadinessProbe).To(BeNil())
+		})
+
+		It("should probe the model server over HTTPS when configured", func() {
+			infScheduler.Spec.ModelServer.LivenessProbe = &llmv1alpha1.ProbeSpec{Scheme: "HTTPS"}
+
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.LivenessProbe.HTTPGet.Scheme).To(Equal(corev1.URISchemeHTTPS))
+			Expect(vllm.StartupProbe.HTTPGet.Scheme).To(BeEmpty())
+		})
+
+		It("should not probe the EPP when its health port is disabled", func() {
+			disabled := false
+			infScheduler.Spec.EndpointPicker.ExposeHealthPort = &disabled
+
+			r := &InferenceSchedulerReconciler{}
+			epp := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(epp.StartupProbe).To(BeNil())
+			Expect(epp.LivenessProbe).To(BeNil())
+		})
+	})
+
+	Context("When owner reference policies are configured", func() {
+		var r *InferenceSchedulerReconciler
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+			r = &InferenceSchedulerReconciler{Scheme: scheme}
+		})
+
+		It("should default to a blocking controller reference", func() {
+			deployment := r.buildModelServerDeployment(infScheduler)
+			Expect(r.setOwnerReference(infScheduler, deployment)).To(Succeed())
+
+			refs := deployment.GetOwnerReferences()
+			Expect(refs).To(HaveLen(1))
+			Expect(*refs[0].Controller).To(BeTrue())
+			Expect(*refs[0].BlockOwnerDeletion).To(BeTrue())
+		})
+
+		It("should apply the policy for the child kind only", func() {
+			disabled := false
+			infScheduler.Spec.OwnerReferences = []llmv1alpha1.OwnerReferencePolicy{
+				{Kind: "HTTPRoute", Controller: &disabled, BlockOwnerDeletion: &disabled},
+			}
+
+			route := r.buildHTTPRoute(infScheduler)
+			Expect(r.setOwnerReference(infScheduler, route)).To(Succeed())
+			refs := route.GetOwnerReferences()
+			Expect(refs).To(HaveLen(1))
+			Expect(refs[0].Controller).To(BeNil())
+			Expect(*refs[0].BlockOwnerDeletion).To(BeFalse())
+
+			service := r.buildModelServerService(infScheduler)
+			Expect(r.setOwnerReference(infScheduler, service)).To(Succeed())
+			Expect(*service.GetOwnerReferences()[0].Controller).To(BeTrue())
+		})
+
+		It("should refuse a non-controller reference on a child controlled by another owner", func() {
+			disabled, controller := false, true
+			infScheduler.Spec.OwnerReferences = []llmv1alpha1.OwnerReferencePolicy{{Kind: "HTTPRoute", Controller: &disabled}}
+
+			route := r.buildHTTPRoute(infScheduler)
+			route.SetOwnerReferences([]metav1.OwnerReference{{
+				APIVersion: "example.com/v1",
+				Kind:       "Application",
+				Name:       "other",
+				UID:        "other-uid",
+				Controller: &controller,
+			}})
+			err := r.setOwnerReference(infScheduler, route)
+			Expect(err).To(BeAssignableToTypeOf(&controllerutil.AlreadyOwnedError{}))
+			Expect(route.GetOwnerReferences()).To(HaveLen(1))
+		})
+	})
+
+	Context("When the model server runs as a StatefulSet", func() {
+		BeforeEach(func() {
+			infScheduler.Spec.ModelServer.Workload = workloadStatefulSet
+		})
+
+		It("should reuse the Deployment pod template", func() {
+			r := &InferenceSchedulerReconciler{}
+			deployment := r.buildModelServerDeployment(infScheduler)
+
+			statefulSet, ok := r.buildModelServerWorkload(infScheduler).(*appsv1.StatefulSet)
+			Expect(ok).To(BeTrue())
+			Expect(statefulSet.Name).To(Equal(deployment.Name))
+			Expect(statefulSet.Spec.Template).To(Equal(deployment.Spec.Template))
+			Expect(statefulSet.Spec.ServiceName).To(Equal("test-scheduler-vllm-headless"))
+		})
+
+		It("should govern the StatefulSet with a headless Service", func() {
+			r := &InferenceSchedulerReconciler{}
+			service := r.buildModelServerHeadlessService(infScheduler)
+			Expect(service.Name).To(Equal("test-scheduler-vllm-headless"))
+			Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(service.Spec.Selector).To(Equal(r.buildModelServerService(infScheduler).Spec.Selector))
+		})
+
+		It("should pause the rollout with a partition", func() {
+			infScheduler.Spec.ModelServer.Replicas = 3
+			infScheduler.Spec.ModelServer.PauseRollout = true
+
+			r := &InferenceSchedulerReconciler{}
+			statefulSet := r.buildModelServerStatefulSet(infScheduler)
+			Expect(*statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
+		})
+	})
+
+	Context("When model server topology spread constraints are configured", func() {
+		It("should keep explicit constraints and default the label selector to the model server pods", func() {
+			infScheduler.Spec.ModelServer.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           2,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+				},
+				{
+					MaxSkew:           1,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "custom"}},
+				},
+			}
+			infScheduler.Spec.ModelServer.SpreadAcrossNodes = true
+
+			r := &InferenceSchedulerReconciler{}
+			deployment := r.buildModelServerDeployment(infScheduler)
+			constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+			// The hostname constraint is configured, so SpreadAcrossNodes adds nothing
+			Expect(constraints).To(HaveLen(2))
+			Expect(constraints[0].MaxSkew).To(Equal(int32(2)))
+			Expect(constraints[0].WhenUnsatisfiable).To(Equal(corev1.DoNotSchedule))
+			Expect(constraints[0].LabelSelector.MatchLabels).To(Equal(deployment.Spec.Selector.MatchLabels))
+			Expect(constraints[0].LabelSelector.MatchLabels).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+			Expect(constraints[1].LabelSelector.MatchLabels).To(Equal(map[string]string{"app": "custom"}))
+			Expect(infScheduler.Spec.ModelServer.TopologySpreadConstraints[0].LabelSelector).To(BeNil())
+		})
+
+		It("should spread across nodes when asked to", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.TopologySpreadConstraints).To(BeEmpty())
+
+			infScheduler.Spec.ModelServer.SpreadAcrossNodes = true
+			deployment := r.buildModelServerDeployment(infScheduler)
+			Expect(deployment.Spec.Template.Spec.TopologySpreadConstraints).To(ConsistOf(corev1.TopologySpreadConstraint{
+				MaxSkew:           1,
+				TopologyKey:       "kubernetes.io/hostname",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: deployment.Spec.Selector.MatchLabels},
+			}))
+		})
+	})
+
+	Context("When EPP topology spread constraints are configured", func() {
+		It("should default the label selector to the EPP pods", func() {
+			infScheduler.Spec.EndpointPicker.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+				},
+				{
+					MaxSkew:           1,
+					TopologyKey:       "kubernetes.io/hostname",
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "custom"}},
+				},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			deployment := r.buildEPPDeployment(infScheduler)
+			constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+			Expect(constraints).To(HaveLen(2))
+			Expect(constraints[0].LabelSelector.MatchLabels).To(Equal(deployment.Spec.Selector.MatchLabels))
+			Expect(constraints[1].LabelSelector.MatchLabels).To(Equal(map[string]string{"app": "custom"}))
+			Expect(infScheduler.Spec.EndpointPicker.TopologySpreadConstraints[0].LabelSelector).To(BeNil())
+		})
+	})
+
+	Context("When the EPP is exposed over a NodePort", func() {
+		It("should keep the ClusterIP Service by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildEPPService(infScheduler).Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		})
+
+		It("should set the configured node ports", func() {
+			grpcNodePort := int32(30902)
+			infScheduler.Spec.EndpointPicker.NodePort = &llmv1alpha1.EPPNodePortSpec{GRPC: &grpcNodePort}
+
+			r := &InferenceSchedulerReconciler{}
+			service := r.buildEPPService(infScheduler)
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			for _, port := range service.Spec.Ports {
+				if port.Name == "grpc" {
+					Expect(port.NodePort).To(Equal(grpcNodePort))
+				} else {
+					Expect(port.NodePort).To(BeZero())
+				}
+			}
+		})
+
+		It("should reject a node port shared by two ports", func() {
+			nodePort := int32(30902)
+			infScheduler.Spec.EndpointPicker.NodePort = &llmv1alpha1.EPPNodePortSpec{GRPC: &nodePort, Health: &nodePort}
+			Expect(validateEPPNodePort(infScheduler.Spec.EndpointPicker)).To(HaveLen(1))
+		})
+	})
+
+	Context("When building the EPP Role", func() {
+		It("should only grant read access to pods and InferencePools by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			rules := r.buildEPPRole(infScheduler).Rules
+			Expect(rules).To(HaveLen(2))
+			Expect(rules[0].Resources).To(Equal([]string{"pods"}))
+			Expect(rules[1].Resources).To(Equal([]string{"inferencepools"}))
+		})
+
+		It("should grant leases and endpointslices when enabled", func() {
+			infScheduler.Spec.EndpointPicker.RBAC = &llmv1alpha1.EPPRBACSpec{LeaderElection: true, EndpointSlices: true}
+
+			r := &InferenceSchedulerReconciler{}
+			rules := r.buildEPPRole(infScheduler).Rules
+			Expect(rules).To(HaveLen(4))
+			Expect(rules[2]).To(Equal(rbacv1.PolicyRule{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			}))
+			Expect(rules[3]).To(Equal(rbacv1.PolicyRule{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "list", "watch"},
+			}))
+		})
+	})
+
+	Context("When configuring the EPP failure mode", func() {
+		failureMode := func() string {
+			r := &InferenceSchedulerReconciler{}
+			mode, _, _ := unstructured.NestedString(r.buildInferencePool(infScheduler).Object, "spec", "endpointPickerRef", "failureMode")
+			return mode
+		}
+
+		It("should fail open by default", func() {
+			Expect(failureMode()).To(Equal("FailOpen"))
+		})
+
+		It("should set the configured failure mode", func() {
+			infScheduler.Spec.EndpointPicker.FailureMode = "FailClose"
+			Expect(failureMode()).To(Equal("FailClose"))
+
+			infScheduler.Spec.EndpointPicker.FailureMode = "FailOpen"
+			Expect(failureMode()).To(Equal("FailOpen"))
+		})
+	})
+
+	Context("When the model server Service is exposed outside the cluster", func() {
+		It("should keep the ClusterIP Service by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			service := r.buildModelServerService(infScheduler)
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(service.Spec.Ports[0].NodePort).To(BeZero())
+		})
+
+		It("should set a NodePort Service with the configured node port", func() {
+			nodePort := int32(30800)
+			infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{Type: "NodePort", NodePort: &nodePort}
+
+			r := &InferenceSchedulerReconciler{}
+			service := r.buildModelServerService(infScheduler)
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			Expect(service.Spec.Ports[0].NodePort).To(Equal(nodePort))
+			Expect(validateModelServerService(infScheduler.Spec.ModelServer.Service)).To(Succeed())
+		})
+
+		It("should set a LoadBalancer Service with an allocated node port", func() {
+			infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{Type: "LoadBalancer"}
+
+			r := &InferenceSchedulerReconciler{}
+			service := r.buildModelServerService(infScheduler)
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+			Expect(service.Spec.Ports[0].NodePort).To(BeZero())
+		})
+
+		It("should keep the headless and extra model server Services off the node port", func() {
+			nodePort := int32(30800)
+			infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{Type: "NodePort", NodePort: &nodePort}
+			infScheduler.Spec.ModelServer.Workload = workloadStatefulSet
+			infScheduler.Spec.ExtraModelServers = []llmv1alpha1.ExtraModelServerSpec{{Name: "small", ModelName: "Qwen/Qwen2.5-0.5B"}}
+
+			r := &InferenceSchedulerReconciler{}
+			headless := r.buildModelServerHeadlessService(infScheduler)
+			Expect(headless.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(headless.Spec.Ports[0].NodePort).To(BeZero())
+
+			for _, obj := range r.buildExtraModelServerObjects(infScheduler, infScheduler.Spec.ExtraModelServers[0]) {
+				if service, ok := obj.(*corev1.Service); ok {
+					Expect(service.Spec.Ports[0].NodePort).To(BeZero())
+				}
+			}
+		})
+
+		It("should reject a node port on a ClusterIP Service", func() {
+			nodePort := int32(30800)
+			infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{NodePort: &nodePort}
+			Expect(validateModelServerService(infScheduler.Spec.ModelServer.Service)).To(MatchError(ContainSubstring("requires a NodePort or LoadBalancer")))
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerService(infScheduler).Spec.Ports[0].NodePort).To(BeZero())
+		})
+	})
+
+	Context("When the Gateway allows routes from other namespaces", func() {
+		It("should only allow routes from the same namespace by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			listeners, _, _ := unstructured.NestedFieldNoCopy(r.buildGateway(infScheduler).Object, "spec", "listeners")
+			from, _, _ := unstructured.NestedString(listeners.([]interface{})[0].(map[string]interface{}), "allowedRoutes", "namespaces", "from")
+			Expect(from).To(Equal("Same"))
+		})
+
+		It("should render a namespace selector", func() {
+			infScheduler.Spec.Gateway.AllowedRoutes = &llmv1alpha1.AllowedRoutesSpec{
+				From:     "Selector",
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shared-gateway": "true"}},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			listeners, _, _ := unstructured.NestedFieldNoCopy(r.buildGateway(infScheduler).Object, "spec", "listeners")
+			namespaces, _, _ := unstructured.NestedMap(listeners.([]interface{})[0].(map[string]interface{}), "allowedRoutes", "namespaces")
+			Expect(namespaces["from"]).To(Equal("Selector"))
+			matchLabels, _, _ := unstructured.NestedStringMap(namespaces, "selector", "matchLabels")
+			Expect(matchLabels).To(Equal(map[string]string{"shared-gateway": "true"}))
+		})
+	})
+
+	Context("When building the HTTPRoute backendRef", func() {
+		backendRef := func(r *InferenceSchedulerReconciler) map[string]interface{} {
+			rules, _, _ := unstructured.NestedFieldNoCopy(r.buildHTTPRoute(infScheduler).Object, "spec", "rules")
+			rule := rules.([]interface{})[0].(map[string]interface{})
+			return rule["backendRefs"].([]interface{})[0].(map[string]interface{})
+		}
+
+		It("should default the port to the InferencePool target port", func() {
+			targetPort := int32(8200)
+			infScheduler.Spec.ModelServer.Port = 8000
+			infScheduler.Spec.ModelServer.TargetPort = &targetPort
+
+			r := &InferenceSchedulerReconciler{}
+			ref := backendRef(r)
+			Expect(ref["kind"]).To(Equal("InferencePool"))
+			Expect(ref["port"]).To(Equal(targetPort))
+			Expect(ref).NotTo(HaveKey("weight"))
+
+			targetPorts, _, _ := unstructured.NestedFieldNoCopy(r.buildInferencePool(infScheduler).Object, "spec", "targetPorts")
+			Expect(targetPorts.([]interface{})[0].(map[string]interface{})["number"]).To(Equal(ref["port"]))
+		})
+
+		It("should use the configured port and weight", func() {
+			port, weight := int32(9000), int32(50)
+			infScheduler.Spec.Gateway.BackendRef = &llmv1alpha1.RouteBackendRefSpec{Port: &port, Weight: &weight}
+
+			ref := backendRef(&InferenceSchedulerReconciler{})
+			Expect(ref["port"]).To(Equal(port))
+			Expect(ref["weight"]).To(Equal(weight))
+		})
+
+		It("should drop the backend in maintenance mode", func() {
+			infScheduler.Spec.MaintenanceMode = true
+
+			r := &InferenceSchedulerReconciler{}
+			rules, _, _ := unstructured.NestedFieldNoCopy(r.buildHTTPRoute(infScheduler).Object, "spec", "rules")
+			rule := rules.([]interface{})[0].(map[string]interface{})
+			Expect(rule["backendRefs"]).To(BeEmpty())
+		})
+	})
+
+	Context("When a model cache volume is configured", func() {
+		It("should mount the volume as the HuggingFace cache", func() {
+			infScheduler.Spec.ModelServer.ModelCache = &llmv1alpha1.ModelCacheSpec{
+				NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/models"},
+			}
+			Expect(validateModelCache(infScheduler.Spec.ModelServer.ModelCache, 1)).To(Succeed())
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("VolumeSource.NFS.Server", "nfs.example.com")))
+
+			vllm := podSpec.Containers[0]
+			Expect(vllm.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "model-cache", MountPath: defaultModelCachePath}))
+			Expect(vllm.Env).To(ContainElement(corev1.EnvVar{Name: "HF_HOME", Value: defaultModelCachePath}))
+		})
+
+		It("should reject more than one volume source", func() {
+			cache := &llmv1alpha1.ModelCacheSpec{
+				NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/models"},
+				CSI: &corev1.CSIVolumeSource{Driver: "csi.example.com"},
+			}
+			Expect(validateModelCache(cache, 1)).NotTo(Succeed())
+		})
+
+		It("should provision a claim and mount it", func() {
+			storageClass := "fast"
+			infScheduler.Spec.ModelServer.ModelCache = &llmv1alpha1.ModelCacheSpec{
+				Claim: &llmv1alpha1.ModelCacheClaimSpec{StorageClassName: &storageClass, Size: resource.MustParse("200Gi")},
+			}
+			Expect(validateModelCache(infScheduler.Spec.ModelServer.ModelCache, 3)).To(Succeed())
+
+			r := &InferenceSchedulerReconciler{}
+			pvc := r.buildModelCachePVC(infScheduler)
+			Expect(pvc.Name).To(Equal("test-scheduler-model-cache"))
+			Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}))
+			Expect(*pvc.Spec.StorageClassName).To(Equal("fast"))
+			Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("200Gi"))
+
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("VolumeSource.PersistentVolumeClaim.ClaimName", "test-scheduler-model-cache")))
+			Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "model-cache", MountPath: defaultModelCachePath}))
+		})
+
+		It("should reject a claim that cannot be shared by several replicas", func() {
+			cache := &llmv1alpha1.ModelCacheSpec{
+				Claim: &llmv1alpha1.ModelCacheClaimSpec{Size: resource.MustParse("200Gi"), AccessMode: corev1.ReadWriteOnce},
+			}
+			Expect(validateModelCache(cache, 1)).To(Succeed())
+			Expect(validateModelCache(cache, 2)).To(MatchError(ContainSubstring("use ReadWriteMany")))
+		})
+
+		It("should keep the fields set when the claim was bound", func() {
+			infScheduler.Spec.ModelServer.ModelCache = &llmv1alpha1.ModelCacheSpec{
+				Claim: &llmv1alpha1.ModelCacheClaimSpec{Size: resource.MustParse("200Gi")},
+			}
+			r := &InferenceSchedulerReconciler{}
+			existing := r.buildModelCachePVC(infScheduler)
+			storageClass := "standard"
+			existing.Spec.StorageClassName = &storageClass
+			existing.Spec.VolumeName = "pvc-1234"
+
+			desired := r.buildModelCachePVC(infScheduler)
+			keepBoundClaim(desired, existing)
+			Expect(desired.Spec.VolumeName).To(Equal("pvc-1234"))
+			Expect(*desired.Spec.StorageClassName).To(Equal("standard"))
+		})
+	})
+
+	Context("When container resources are not fully set", func() {
+		It("should default the EPP requests and limits", func() {
+			r := &InferenceSchedulerReconciler{}
+			resources := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Requests).To(Equal(DefaultResources().EndpointPicker.Requests))
+			Expect(resources.Limits).To(Equal(DefaultResources().EndpointPicker.Limits))
+		})
+
+		It("should default only the model server requests", func() {
+			r := &InferenceSchedulerReconciler{}
+			resources := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Requests).To(Equal(DefaultResources().ModelServer.Requests))
+			Expect(resources.Limits).To(BeEmpty())
+		})
+
+		It("should use the configured defaults, including model server limits", func() {
+			defaults := DefaultResources()
+			defaults.ModelServer.Limits = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			}
+			defaults.EndpointPicker.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}
+			tensorParallelSize := int32(2)
+			infScheduler.Spec.ModelServer.TensorParallelSize = &tensorParallelSize
+			infScheduler.Spec.ModelServer.PreloadModel = &llmv1alpha1.PreloadModelSpec{}
+
+			r := &InferenceSchedulerReconciler{ResourceDefaults: &defaults}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Containers[0].Resources.Limits).To(Equal(corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+				gpuResourceName:       resource.MustParse("2"),
+			}))
+			Expect(podSpec.InitContainers[0].Resources.Limits).To(Equal(defaults.ModelServer.Limits))
+			Expect(defaults.ModelServer.Limits).NotTo(HaveKey(gpuResourceName))
+
+			eppResources := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Resources
+			Expect(eppResources.Limits).To(Equal(defaults.EndpointPicker.Limits))
+		})
+
+		It("should default the resources user init containers and sidecars leave unset", func() {
+			infScheduler.Spec.ModelServer.InitContainers = []corev1.Container{{Name: "fetch", Image: "busybox"}}
+			infScheduler.Spec.ModelServer.Sidecars = []corev1.Container{{
+				Name:  "proxy",
+				Image: "envoy",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			}}
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.InitContainers[0].Resources.Requests).To(Equal(DefaultResources().Containers.Requests))
+			Expect(podSpec.InitContainers[0].Resources.Limits).To(Equal(DefaultResources().Containers.Limits))
+			Expect(podSpec.Containers[1].Resources.Limits[corev1.ResourceMemory]).To(Equal(resource.MustParse("1Gi")))
+			Expect(podSpec.Containers[1].Resources.Limits[corev1.ResourceCPU]).To(Equal(DefaultResources().Containers.Limits[corev1.ResourceCPU]))
+			Expect(infScheduler.Spec.ModelServer.Sidecars[0].Resources.Requests).To(BeNil())
+		})
+
+		It("should keep user values and stay consistent with them", func() {
+			infScheduler.Spec.ModelServer.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("80Gi")},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("250m"),
+					gpuResourceName:    resource.MustParse("1"),
+				},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			resources := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Requests.Cpu().String()).To(Equal("250m"))
+			Expect(resources.Limits).NotTo(HaveKey(corev1.ResourceMemory))
+			Expect(resources.Limits[gpuResourceName]).To(Equal(resource.MustParse("1")))
+			Expect(infScheduler.Spec.ModelServer.Resources.Requests).To(HaveLen(1))
+		})
+	})
+
+	Context("When the EPP config apiVersion is configured", func() {
+		It("should default to the current schema version", func() {
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).To(HavePrefix("apiVersion: inference.networking.x-k8s.io/v1alpha1\n"))
+		})
+
+		It("should write the configured version", func() {
+			infScheduler.Spec.EndpointPicker.ConfigAPIVersion = "inference.networking.x-k8s.io/v1beta1"
+
+			r := &InferenceSchedulerReconciler{}
+			config := r.buildEPPConfigMap(infScheduler).Data["plugins.yaml"]
+			Expect(config).To(HavePrefix("apiVersion: inference.networking.x-k8s.io/v1beta1\n"))
+		})
+	})
+
+	Context("When comparing desired and existing children", func() {
+		var r *InferenceSchedulerReconciler
+
+		// applied returns desired as it would be read back, including server-side defaults
+		applied := func(desired *appsv1.Deployment) *appsv1.Deployment {
+			existing := desired.DeepCopy()
+			existing.ResourceVersion = "42"
+			existing.Spec.RevisionHistoryLimit = new(int32)
+			existing.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+			existing.Status.Replicas = 2
+			return existing
+		}
+
+		BeforeEach(func() {
+			r = &InferenceSchedulerReconciler{}
+		})
+
+		It("should skip the update when only defaults differ", func() {
+			desired := r.buildModelServerDeployment(infScheduler)
+			setAppliedHash(desired)
+			Expect(upToDate(desired, applied(desired))).To(BeTrue())
+		})
+
+		It("should update when the desired state changed", func() {
+			previous := r.buildModelServerDeployment(infScheduler)
+			setAppliedHash(previous)
+
+			infScheduler.Spec.ModelServer.PauseRollout = true
+			desired := r.buildModelServerDeployment(infScheduler)
+			setAppliedHash(desired)
+			Expect(upToDate(desired, applied(previous))).To(BeFalse())
+		})
+
+		It("should update when the existing object was edited", func() {
+			desired := r.buildModelServerDeployment(infScheduler)
+			setAppliedHash(desired)
+
+			existing := applied(desired)
+			existing.Spec.Template.Spec.Containers[0].Image = "vllm/vllm-openai:edited"
+			Expect(upToDate(desired, existing)).To(BeFalse())
+		})
+
+		It("should compare unstructured children", func() {
+			desired := r.buildInferencePool(infScheduler)
+			setAppliedHash(desired)
+
+			content, err := comparableContent(desired)
+			Expect(err).NotTo(HaveOccurred())
+			existing := &unstructured.Unstructured{Object: content}
+			Expect(upToDate(desired, existing)).To(BeTrue())
+
+			Expect(unstructured.SetNestedField(existing.Object, "FailClose", "spec", "endpointPickerRef", "failureMode")).To(Succeed())
+			Expect(upToDate(desired, existing)).To(BeFalse())
+		})
+	})
+
+	Context("When model server scheduling constraints are configured", func() {
+		It("should copy tolerations without requiring a nodeSelector", func() {
+			tolerations := []corev1.Toleration{{
+				Key:      "nvidia.com/gpu",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}}
+			infScheduler.Spec.ModelServer.Tolerations = tolerations
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Tolerations).To(Equal(tolerations))
+			Expect(podSpec.NodeSelector).To(BeNil())
+			Expect(podSpec.Affinity).To(BeNil())
+		})
+
+		It("should copy the nodeSelector and affinity", func() {
+			nodeSelector := map[string]string{"nvidia.com/gpu.present": "true"}
+			affinity := &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      "nvidia.com/gpu.product",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"NVIDIA-A100-SXM4-80GB"},
+							}},
+						}},
+					},
+				},
+			}
+			infScheduler.Spec.ModelServer.NodeSelector = nodeSelector
+			infScheduler.Spec.ModelServer.Affinity = affinity
+
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerStatefulSet(infScheduler).Spec.Template.Spec
+			Expect(podSpec.NodeSelector).To(Equal(nodeSelector))
+			Expect(podSpec.Affinity).To(Equal(affinity))
+		})
+	})
+
+	Context("When labeling the Deployments", func() {
+		It("should label the Deployments without changing their selectors or pods", func() {
+			infScheduler.Spec.ModelServer.DeploymentLabels = map[string]string{"cost-center": "ml", "app": "ignored"}
+			infScheduler.Spec.EndpointPicker.DeploymentLabels = map[string]string{"cost-center": "platform"}
+
+			r := &InferenceSchedulerReconciler{}
+			modelServer := r.buildModelServerDeployment(infScheduler)
+			Expect(modelServer.Labels).To(HaveKeyWithValue("cost-center", "ml"))
+			Expect(modelServer.Labels).To(HaveKeyWithValue("app", "vllm"))
+			Expect(modelServer.Spec.Selector.MatchLabels).NotTo(HaveKey("cost-center"))
+			Expect(modelServer.Spec.Template.Labels).NotTo(HaveKey("cost-center"))
+
+			epp := r.buildEPPDeployment(infScheduler)
+			Expect(epp.Labels).To(HaveKeyWithValue("cost-center", "platform"))
+			Expect(epp.Spec.Selector.MatchLabels).NotTo(HaveKey("cost-center"))
+			Expect(epp.Spec.Template.Labels).NotTo(HaveKey("cost-center"))
+		})
+	})
+
+	Context("When annotations are passed through to the children", func() {
+		It("should annotate the model server workload and Services but not its pods", func() {
+			infScheduler.Spec.ModelServer.Annotations = map[string]string{"mesh.example.com/inject": "true", "team": "ml"}
+			infScheduler.Spec.ModelServer.DeploymentAnnotations = map[string]string{"team": "inference"}
+			infScheduler.Spec.ModelServer.Workload = workloadStatefulSet
+
+			r := &InferenceSchedulerReconciler{}
+			statefulSet := r.buildModelServerStatefulSet(infScheduler)
+			Expect(statefulSet.Annotations).To(HaveKeyWithValue("mesh.example.com/inject", "true"))
+			Expect(statefulSet.Annotations).To(HaveKeyWithValue("team", "inference"))
+			Expect(statefulSet.Spec.Template.Annotations).NotTo(HaveKey("mesh.example.com/inject"))
+
+			Expect(r.buildModelServerService(infScheduler).Annotations).To(Equal(infScheduler.Spec.ModelServer.Annotations))
+			Expect(r.buildModelServerHeadlessService(infScheduler).Annotations).To(Equal(infScheduler.Spec.ModelServer.Annotations))
+		})
+
+		It("should annotate the EPP Deployment and Service", func() {
+			infScheduler.Spec.EndpointPicker.Annotations = map[string]string{"mesh.example.com/inject": "false"}
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildEPPDeployment(infScheduler).Annotations).To(HaveKeyWithValue("mesh.example.com/inject", "false"))
+			Expect(r.buildEPPService(infScheduler).Annotations).To(HaveKeyWithValue("mesh.example.com/inject", "false"))
+			Expect(r.buildModelServerService(infScheduler).Annotations).To(BeEmpty())
+		})
+
+		It("should annotate the Gateway", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildGateway(infScheduler).GetAnnotations()).To(BeEmpty())
+
+			infScheduler.Spec.Gateway.Annotations = map[string]string{"networking.gke.io/certmap": "inference"}
+			Expect(r.buildGateway(infScheduler).GetAnnotations()).To(Equal(infScheduler.Spec.Gateway.Annotations))
+		})
+
+		It("should not let annotations override the ones managed by the operator", func() {
+			infScheduler.Spec.ModelServer.Annotations = map[string]string{operatorVersionAnnotation: "v0.0.0", "team": "ml"}
+			infScheduler.Spec.Gateway.Annotations = map[string]string{operatorVersionAnnotation: "v0.0.0"}
+
+			r := &InferenceSchedulerReconciler{}
+			service := r.buildModelServerService(infScheduler)
+			gateway := r.buildGateway(infScheduler)
+			for _, obj := range []client.Object{service, gateway} {
+				setManagedMetadata(obj)
+				Expect(obj.GetAnnotations()).To(HaveKeyWithValue(operatorVersionAnnotation, Version))
+			}
+			Expect(service.Annotations).To(HaveKeyWithValue("team", "ml"))
+			// The builders copy the map, so the spec is left as it was
+			Expect(infScheduler.Spec.ModelServer.Annotations).To(HaveKeyWithValue(operatorVersionAnnotation, "v0.0.0"))
+		})
+	})
+
+	Context("When image pull secrets are configured", func() {
+		It("should pull both images with the shared secrets", func() {
+			secrets := []corev1.LocalObjectReference{{Name: "registry-creds"}}
+			infScheduler.Spec.ImagePullSecrets = secrets
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.ImagePullSecrets).To(Equal(secrets))
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Spec.ImagePullSecrets).To(Equal(secrets))
+		})
+
+		It("should let a component override the shared secrets", func() {
+			infScheduler.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry-creds"}}
+			infScheduler.Spec.EndpointPicker.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "epp-creds"}}
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "registry-creds"}))
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Spec.ImagePullSecrets).To(ConsistOf(corev1.LocalObjectReference{Name: "epp-creds"}))
+		})
+	})
+
+	Context("When configuring model server logging", func() {
+		It("should disable request logging when configured", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Args).NotTo(ContainElement("--disable-log-requests"))
+
+			infScheduler.Spec.ModelServer.DisableLogRequests = true
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Args).To(ContainElement("--disable-log-requests"))
+		})
+
+		It("should mount a JSON logging config for the json log format", func() {
+			infScheduler.Spec.ModelServer.LogFormat = "json"
+
+			r := &InferenceSchedulerReconciler{}
+			configMap := r.buildModelServerLoggingConfigMap(infScheduler)
+			Expect(configMap.Name).To(Equal("test-scheduler-vllm-logging"))
+			var config map[string]interface{}
+			Expect(json.Unmarshal([]byte(configMap.Data["logging.json"]), &config)).To(Succeed())
+
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("ConfigMap.Name", configMap.Name)))
+			Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name:  "VLLM_LOGGING_CONFIG_PATH",
+				Value: "/etc/vllm/logging/logging.json",
+			}))
+		})
+
+		It("should keep vLLM's default logging for the text log format", func() {
+			r := &InferenceSchedulerReconciler{}
+			podSpec := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec
+			Expect(podSpec.Volumes).NotTo(ContainElement(HaveField("Name", "logging-config")))
+			Expect(podSpec.Containers[0].Env).NotTo(ContainElement(HaveField("Name", "VLLM_LOGGING_CONFIG_PATH")))
+		})
+	})
+
+	Context("When building the PrometheusRule", func() {
+		alertExprs := func(rule *unstructured.Unstructured) map[string]string {
+			groups, _, _ := unstructured.NestedFieldNoCopy(rule.Object, "spec", "groups")
+			exprs := map[string]string{}
+			for _, group := range groups.([]interface{}) {
+				for _, rule := range group.(map[string]interface{})["rules"].([]interface{}) {
+					rule := rule.(map[string]interface{})
+					exprs[rule["alert"].(string)] = rule["expr"].(string)
+				}
+			}
+			return exprs
+		}
+
+		It("should alert on readiness, model server replicas and the EPP", func() {
+			infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{
+				PrometheusRule: true,
+				Labels:         map[string]string{"release": "prometheus"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			rule := r.buildPrometheusRule(infScheduler)
+			Expect(rule.GetKind()).To(Equal("PrometheusRule"))
+			Expect(rule.GetName()).To(Equal("test-scheduler-alerts"))
+			Expect(rule.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+
+			exprs := alertExprs(rule)
+			Expect(exprs).To(HaveLen(3))
+			Expect(exprs["InferenceSchedulerNotReady"]).To(ContainSubstring(`inferencescheduler="test-scheduler"`))
+			Expect(exprs["InferenceSchedulerModelServerReplicasLow"]).To(ContainSubstring(`deployment="test-scheduler-vllm"`))
+			Expect(exprs["InferenceSchedulerEPPDown"]).To(ContainSubstring(`deployment="test-scheduler-epp"`))
+		})
+
+		It("should use StatefulSet metrics for a StatefulSet model server", func() {
+			infScheduler.Spec.ModelServer.Workload = "StatefulSet"
+
+			r := &InferenceSchedulerReconciler{}
+			exprs := alertExprs(r.buildPrometheusRule(infScheduler))
+			Expect(exprs["InferenceSchedulerModelServerReplicasLow"]).To(ContainSubstring(`statefulset="test-scheduler-vllm"`))
+		})
+	})
+
+	Context("When building the ServiceMonitor", func() {
+		endpoints := func(monitor *unstructured.Unstructured) []interface{} {
+			eps, _, _ := unstructured.NestedFieldNoCopy(monitor.Object, "spec", "endpoints")
+			return eps.([]interface{})
+		}
+
+		It("should scrape the EPP metrics port of this InferenceScheduler", func() {
+			infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{
+				ServiceMonitor: true,
+				Labels:         map[string]string{"release": "prometheus"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			monitor := r.buildServiceMonitor(infScheduler)
+			Expect(monitor.GetKind()).To(Equal("ServiceMonitor"))
+			Expect(monitor.GetName()).To(Equal("test-scheduler-metrics"))
+			Expect(monitor.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+			Expect(endpoints(monitor)).To(ConsistOf(
+				map[string]interface{}{"port": "metrics", "path": "/metrics"},
+			))
+
+			matchLabels, _, _ := unstructured.NestedFieldNoCopy(monitor.Object, "spec", "selector", "matchLabels")
+			Expect(matchLabels).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+			Expect(r.buildEPPService(infScheduler).Labels).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+			Expect(r.buildEPPService(infScheduler).Spec.Selector).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+		})
+
+		It("should add the vLLM metrics on the model server Service port", func() {
+			infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{ServiceMonitor: true, ScrapeModelServer: true}
+			infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{PortName: "vllm-http"}
+
+			r := &InferenceSchedulerReconciler{}
+			monitor := r.buildServiceMonitor(infScheduler)
+			Expect(endpoints(monitor)).To(ConsistOf(
+				map[string]interface{}{"port": "metrics", "path": "/metrics"},
+				map[string]interface{}{"port": "vllm-http", "path": "/metrics"},
+			))
+			Expect(r.buildModelServerService(infScheduler).Labels).To(HaveKeyWithValue(instanceLabel, "test-scheduler"))
+		})
+
+		It("should not be built when disabled or with nothing to scrape", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildServiceMonitor(infScheduler)).To(BeNil())
+
+			exposeMetrics := false
+			infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{ServiceMonitor: true}
+			infScheduler.Spec.EndpointPicker.ExposeMetricsPort = &exposeMetrics
+			Expect(r.buildServiceMonitor(infScheduler)).To(BeNil())
+		})
+	})
+
+	Context("When Prometheus scrape annotations are enabled", func() {
+		It("should not annotate the model server pods by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Annotations).To(BeEmpty())
+		})
+
+		It("should annotate the model server pods with the vLLM metrics port and path", func() {
+			infScheduler.Spec.ModelServer.Port = 8080
+			infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{ServiceMonitor: true, ScrapeAnnotations: true}
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Template.Annotations).To(Equal(map[string]string{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   "8080",
+				"prometheus.io/path":   "/metrics",
+			}))
+			// The ServiceMonitor is still created alongside
+			Expect(r.buildServiceMonitor(infScheduler)).NotTo(BeNil())
+		})
+	})
+
+	Context("When building the Grafana dashboard", func() {
+		It("should not create a dashboard by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildDashboardConfigMap(infScheduler)).To(BeNil())
+		})
+
+		It("should label the dashboard for the sidecar and scope it to the instance", func() {
+			infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{Dashboard: true, Labels: map[string]string{"team": "ml"}}
+			infScheduler.Spec.ExtraModelServers = []llmv1alpha1.ExtraModelServerSpec{{Name: "small", ModelName: "Qwen/Qwen2.5-0.5B"}}
+
+			r := &InferenceSchedulerReconciler{}
+			configMap := r.buildDashboardConfigMap(infScheduler)
+			Expect(configMap.Name).To(Equal("test-scheduler-dashboard"))
+			Expect(configMap.Labels).To(Equal(map[string]string{"grafana_dashboard": "1", "team": "ml"}))
+			Expect(configMap.Data).To(HaveKey("default-test-scheduler.json"))
+
+			var dashboard struct {
+				UID    string `json:"uid"`
+				Title  string `json:"title"`
+				Panels []struct {
+					Title   string `json:"title"`
+					Targets []struct {
+						Expr string `json:"expr"`
+					} `json:"targets"`
+				} `json:"panels"`
+			}
+			Expect(json.Unmarshal([]byte(configMap.Data["default-test-scheduler.json"]), &dashboard)).To(Succeed())
+			Expect(len(dashboard.UID)).To(BeNumerically("<=", 40))
+			Expect(dashboard.Title).To(ContainSubstring("test-scheduler"))
+			Expect(dashboard.Panels).To(HaveLen(4))
+			for _, panel := range dashboard.Panels {
+				for _, target := range panel.Targets {
+					Expect(target.Expr).To(ContainSubstring(`namespace="default"`))
+					Expect(target.Expr).To(Or(
+						ContainSubstring(`service=~"test-scheduler-vllm|test-scheduler-small-vllm"`),
+						ContainSubstring(`name="test-scheduler-pool"`),
+					))
+				}
+			}
+		})
+	})
+
+	Context("When the EPP config is overridden by a ConfigMap", func() {
+		BeforeEach(func() {
+			infScheduler.Spec.EndpointPicker.PluginConfigMapRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "custom-epp-config"},
+				Key:                  "config.yaml",
+			}
+		})
+
+		It("should not generate a ConfigMap", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildEPPConfigMap(infScheduler)).To(BeNil())
+		})
+
+		It("should mount the referenced key as plugins.yaml", func() {
+			r := &InferenceSchedulerReconciler{}
+			deployment := r.buildEPPDeployment(infScheduler)
+			Expect(deployment.Spec.Template.Spec.Volumes).To(HaveLen(1))
+			configMap := deployment.Spec.Template.Spec.Volumes[0].ConfigMap
+			Expect(configMap).NotTo(BeNil())
+			Expect(configMap.Name).To(Equal("custom-epp-config"))
+			Expect(configMap.Items).To(Equal([]corev1.KeyToPath{{Key: "config.yaml", Path: "plugins.yaml"}}))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--config-file=/config/plugins.yaml"))
+			Expect(deployment.Spec.Template.Annotations).NotTo(HaveKey(configHashAnnotation))
+		})
+
+		It("should reject scorers configured alongside the reference", func() {
+			Expect(validation.PluginConfigSource(infScheduler.Spec.EndpointPicker)).To(Succeed())
+
+			infScheduler.Spec.EndpointPicker.Plugins.AdditionalScorers = []llmv1alpha1.AdditionalScorerPlugin{{Type: "queue-scorer"}}
+			Expect(validation.PluginConfigSource(infScheduler.Spec.EndpointPicker)).To(MatchError(ContainSubstring("pluginConfigMapRef")))
+		})
+	})
+
+	Context("When hashing the EPP config", func() {
+		It("should stamp the plugin config hash on the EPP pod template", func() {
+			r := &InferenceSchedulerReconciler{}
+			hash := r.buildEPPConfigMap(infScheduler).Annotations[configHashAnnotation]
+			Expect(hash).NotTo(BeEmpty())
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Annotations).To(HaveKeyWithValue(configHashAnnotation, hash))
+		})
+
+		It("should keep the hash stable until the plugin config changes", func() {
+			weight := 3.0
+			infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer = &llmv1alpha1.ScorerPlugin{
+				Enabled:    true,
+				Parameters: map[string]string{"a": "1", "b": "2", "c": "3"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			hash := r.buildEPPDeployment(infScheduler).Spec.Template.Annotations[configHashAnnotation]
+			for i := 0; i < 10; i++ {
+				Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Annotations[configHashAnnotation]).To(Equal(hash))
+			}
+
+			infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer.Weight = &weight
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Annotations[configHashAnnotation]).NotTo(Equal(hash))
+		})
+
+		It("should summarize the enabled plugins in render order", func() {
+			weight := 3.0
+			infScheduler.Spec.EndpointPicker.Plugins.PrefixCacheScorer = &llmv1alpha1.ScorerPlugin{Enabled: true, Weight: &weight}
+			infScheduler.Spec.EndpointPicker.Plugins.LoadAwareScorer = &llmv1alpha1.ScorerPlugin{Enabled: true}
+			infScheduler.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer = &llmv1alpha1.ScorerPlugin{Enabled: false}
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(activePlugins(r.buildEPPConfigMap(infScheduler))).To(Equal([]string{"load-aware-scorer=1.0", "prefix-cache-scorer=3.0"}))
+		})
+	})
+
+	Context("When the model server is autoscaled", func() {
+		BeforeEach(func() {
+			infScheduler.UID = "test-uid"
+			infScheduler.Spec.ModelServer.Autoscaling = &llmv1alpha1.AutoscalingSpec{MaxReplicas: 8}
+		})
+
+		It("should build an HPA targeting the model server workload", func() {
+			r := &InferenceSchedulerReconciler{}
+			hpa := r.buildModelServerHPA(infScheduler)
+			Expect(hpa.Name).To(Equal("test-scheduler-vllm"))
+			Expect(hpa.Spec.ScaleTargetRef.Kind).To(Equal("Deployment"))
+			Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal("test-scheduler-vllm"))
+			Expect(*hpa.Spec.MinReplicas).To(Equal(int32(1)))
+			Expect(hpa.Spec.MaxReplicas).To(Equal(int32(8)))
+			Expect(hpa.Spec.Metrics).To(HaveLen(1))
+			Expect(hpa.Spec.Metrics[0].Resource.Name).To(Equal(corev1.ResourceCPU))
+
+			infScheduler.Spec.ModelServer.Workload = "StatefulSet"
+			Expect(r.buildModelServerHPA(infScheduler).Spec.ScaleTargetRef.Kind).To(Equal("StatefulSet"))
+		})
+
+		It("should leave the replica count to the HPA", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildModelServerDeployment(infScheduler).Spec.Replicas).To(BeNil())
+
+			infScheduler.Spec.ModelServer.PauseRollout = true
+			statefulSet := r.buildModelServerStatefulSet(infScheduler)
+			Expect(statefulSet.Spec.Replicas).To(BeNil())
+			Expect(*statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(8)))
+		})
+
+		It("should create the HPA and not reset the scaled replicas on later reconciles", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+			r := &InferenceSchedulerReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Scheme: scheme,
+			}
+
+			hpa := r.buildModelServerHPA(infScheduler)
+			Expect(r.createOrUpdate(ctx, hpa, infScheduler)).To(Succeed())
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(hpa), &autoscalingv2.HorizontalPodAutoscaler{})).To(Succeed())
+
+			// The HPA has scaled the Deployment out since it was created
+			deployment := r.buildModelServerDeployment(infScheduler)
+			scaled := int32(5)
+			deployment.Spec.Replicas = &scaled
+			Expect(r.createOrUpdate(ctx, deployment, infScheduler)).To(Succeed())
+
+			for i := 0; i < 2; i++ {
+				Expect(r.createOrUpdate(ctx, r.buildModelServerDeployment(infScheduler), infScheduler)).To(Succeed())
+				existing := &appsv1.Deployment{}
+				Expect(r.Get(ctx, client.ObjectKeyFromObject(deployment), existing)).To(Succeed())
+				Expect(existing.Spec.Replicas).To(HaveValue(Equal(int32(5))))
+			}
+		})
+	})
+
+	Context("When the model server is warmed up", func() {
+		It("should keep the HTTP readiness probe unless warmup is enabled", func() {
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.ReadinessProbe.HTTPGet).NotTo(BeNil())
+			Expect(vllm.ReadinessProbe.Exec).To(BeNil())
+		})
+
+		It("should send a warmup completion from the readiness probe", func() {
+			infScheduler.Spec.ModelServer.Port = 8000
+			infScheduler.Spec.ModelServer.ServedModelNames = []string{"qwen"}
+			infScheduler.Spec.ModelServer.Warmup = &llmv1alpha1.WarmupSpec{}
+
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.ReadinessProbe.HTTPGet).To(BeNil())
+			Expect(vllm.ReadinessProbe.TimeoutSeconds).To(Equal(int32(60)))
+			// The startup probe still gates the readiness probe on /health
+			Expect(vllm.StartupProbe.HTTPGet).NotTo(BeNil())
+
+			command := vllm.ReadinessProbe.Exec.Command
+			Expect(command[:2]).To(Equal([]string{"python3", "-c"}))
+			Expect(command[3:]).To(HaveLen(4))
+			Expect(command[3]).To(Equal("http://localhost:8000"))
+			Expect(command[4]).To(Equal("/v1/completions"))
+			Expect(command[6]).To(Equal("60"))
+
+			var body map[string]interface{}
+			Expect(json.Unmarshal([]byte(command[5]), &body)).To(Succeed())
+			Expect(body).To(HaveKeyWithValue("model", "qwen"))
+			Expect(body).To(HaveKeyWithValue("max_tokens", BeNumerically("==", 1)))
+		})
+	})
+
+	Context("When the Gateway listener section name is configured", func() {
+		parentRef := func(route *unstructured.Unstructured) map[string]interface{} {
+			parentRefs, _, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "parentRefs")
+			return parentRefs.([]interface{})[0].(map[string]interface{})
+		}
+		listenerName := func(gateway *unstructured.Unstructured) interface{} {
+			listeners, _, _ := unstructured.NestedFieldNoCopy(gateway.Object, "spec", "listeners")
+			return listeners.([]interface{})[0].(map[string]interface{})["name"]
+		}
+
+		It("should attach the routes to all listeners by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(listenerName(r.buildGateway(infScheduler))).To(Equal("http"))
+			Expect(parentRef(r.buildHTTPRoute(infScheduler))).NotTo(HaveKey("sectionName"))
+			Expect(parentRef(r.buildHealthCheckHTTPRoute(infScheduler))).NotTo(HaveKey("sectionName"))
+		})
+
+		It("should attach the routes to the named listener", func() {
+			infScheduler.Spec.Gateway.SectionName = "inference"
+
+			r := &InferenceSchedulerReconciler{}
+			Expect(listenerName(r.buildGateway(infScheduler))).To(Equal("inference"))
+			Expect(parentRef(r.buildHTTPRoute(infScheduler))).To(HaveKeyWithValue("sectionName", "inference"))
+			Expect(parentRef(r.buildHealthCheckHTTPRoute(infScheduler))).To(HaveKeyWithValue("sectionName", "inference"))
+		})
+	})
+
+	Context("When the Gateway terminates TLS", func() {
+		listeners := func(gateway *unstructured.Unstructured) []interface{} {
+			listeners, _, _ := unstructured.NestedFieldNoCopy(gateway.Object, "spec", "listeners")
+			return listeners.([]interface{})
+		}
+		sectionName := func(route *unstructured.Unstructured) interface{} {
+			parentRefs, _, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "parentRefs")
+			return parentRefs.([]interface{})[0].(map[string]interface{})["sectionName"]
+		}
+
+		It("should only create an HTTP listener without TLS", func() {
+			infScheduler.Spec.Gateway.ListenerPort = 80
+
+			r := &InferenceSchedulerReconciler{}
+			gateway := r.buildGateway(infScheduler)
+			Expect(listeners(gateway)).To(HaveLen(1))
+			listener := listeners(gateway)[0].(map[string]interface{})
+			Expect(listener).To(HaveKeyWithValue("protocol", "HTTP"))
+			Expect(listener).To(HaveKeyWithValue("port", int32(80)))
+			Expect(listener).NotTo(HaveKey("tls"))
+		})
+
+		It("should terminate TLS on an HTTPS listener the routes attach to", func() {
+			infScheduler.Spec.Gateway.TLS = &llmv1alpha1.GatewayTLSSpec{
+				CertificateSecretName: "inference-tls",
+				Hostname:              "inference.example.com",
+				Port:                  443,
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			gateway := r.buildGateway(infScheduler)
+			Expect(listeners(gateway)).To(HaveLen(1))
+			listener := listeners(gateway)[0].(map[string]interface{})
+			Expect(listener).To(HaveKeyWithValue("name", "https"))
+			Expect(listener).To(HaveKeyWithValue("protocol", "HTTPS"))
+			Expect(listener).To(HaveKeyWithValue("port", int32(443)))
+			Expect(listener).To(HaveKeyWithValue("hostname", "inference.example.com"))
+			Expect(listener["tls"]).To(Equal(map[string]interface{}{
+				"mode": "Terminate",
+				"certificateRefs": []interface{}{
+					map[string]interface{}{"kind": "Secret", "name": "inference-tls"},
+				},
+			}))
+
+			Expect(sectionName(r.buildHTTPRoute(infScheduler))).To(Equal("https"))
+			Expect(sectionName(r.buildHealthCheckHTTPRoute(infScheduler))).To(Equal("https"))
+			Expect(validateGatewayListeners(listeners(gateway))).To(BeEmpty())
+		})
+
+		It("should keep the HTTP listener for a redirect to HTTPS", func() {
+			infScheduler.Spec.Gateway.ListenerPort = 80
+			infScheduler.Spec.Gateway.TLS = &llmv1alpha1.GatewayTLSSpec{
+				CertificateSecretName: "inference-tls",
+				Port:                  8443,
+				HTTPRedirect:          true,
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			gateway := r.buildGateway(infScheduler)
+			Expect(listeners(gateway)).To(ConsistOf(
+				HaveKeyWithValue("name", "http"),
+				HaveKeyWithValue("name", "https"),
+			))
+			Expect(validateGatewayListeners(listeners(gateway))).To(BeEmpty())
+			Expect(sectionName(r.buildHTTPRoute(infScheduler))).To(Equal("https"))
+
+			redirect := r.buildHTTPSRedirectHTTPRoute(infScheduler)
+			Expect(redirect.GetName()).To(Equal("test-scheduler-https-redirect"))
+			Expect(sectionName(redirect)).To(Equal("http"))
+			filter, _, _ := unstructured.NestedFieldNoCopy(redirect.Object, "spec", "rules")
+			Expect(filter.([]interface{})[0].(map[string]interface{})["filters"]).To(ConsistOf(
+				HaveKeyWithValue("requestRedirect", map[string]interface{}{
+					"scheme":     "https",
+					"statusCode": int64(301),
+					"port":       int32(8443),
+				}),
+			))
+		})
+	})
+
+	Context("When HTTPRoute hostnames and paths are configured", func() {
+		It("should match the /v1/ prefix on any hostname by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			route := r.buildHTTPRoute(infScheduler)
+			_, found, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "hostnames")
+			Expect(found).To(BeFalse())
+			matches, _, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "rules")
+			Expect(matches.([]interface{})[0].(map[string]interface{})["matches"]).To(Equal([]interface{}{
+				map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/v1/"}},
+			}))
+		})
+
+		It("should render the configured hostnames and paths", func() {
+			infScheduler.Spec.Gateway.HealthCheckRoute = true
+			infScheduler.Spec.Gateway.Route = &llmv1alpha1.RouteSpec{
+				Hostnames: []string{"qwen.example.com", "*.qwen.example.com"},
+				Paths: []llmv1alpha1.RoutePathMatch{
+					{Value: "/v1/"},
+					{Type: "Exact", Value: "/v1/models"},
+				},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			route := r.buildHTTPRoute(infScheduler)
+			hostnames, _, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "hostnames")
+			Expect(hostnames).To(Equal([]interface{}{"qwen.example.com", "*.qwen.example.com"}))
+			rules, _, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "rules")
+			Expect(rules.([]interface{})[0].(map[string]interface{})["matches"]).To(Equal([]interface{}{
+				map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/v1/"}},
+				map[string]interface{}{"path": map[string]interface{}{"type": "Exact", "value": "/v1/models"}},
+			}))
+
+			healthHostnames, _, _ := unstructured.NestedFieldNoCopy(r.buildHealthCheckHTTPRoute(infScheduler).Object, "spec", "hostnames")
+			Expect(healthHostnames).To(Equal(hostnames))
+		})
+	})
+
+	Context("When an existing Gateway is referenced", func() {
+		It("should reject settings for a Gateway the operator creates", func() {
+			gateway := llmv1alpha1.GatewaySpec{ExistingGatewayRef: &llmv1alpha1.GatewayReference{Name: "shared"}}
+			Expect(validateExistingGateway(gateway)).To(BeEmpty())
+
+			// The API server defaults className, so the default is accepted
+			gateway.ClassName = "kgateway"
+			Expect(validateExistingGateway(gateway)).To(BeEmpty())
+
+			gateway.ClassName = "istio"
+			gateway.TLS = &llmv1alpha1.GatewayTLSSpec{CertificateSecretName: "inference-tls"}
+			Expect(validateExistingGateway(gateway)).To(ConsistOf(
+				ContainSubstring("className"),
+				ContainSubstring("tls"),
+			))
+		})
+
+		It("should attach the routes to the referenced Gateway", func() {
+			infScheduler.Spec.Gateway.SectionName = "inference"
+			infScheduler.Spec.Gateway.ExistingGatewayRef = &llmv1alpha1.GatewayReference{Name: "shared"}
+
+			r := &InferenceSchedulerReconciler{}
+			parentRefs, _, _ := unstructured.NestedFieldNoCopy(r.buildHTTPRoute(infScheduler).Object, "spec", "parentRefs")
+			Expect(parentRefs).To(ConsistOf(map[string]interface{}{
+				"name":        "shared",
+				"namespace":   "default",
+				"sectionName": "inference",
+			}))
+
+			for _, obj := range BuildChildObjects(infScheduler) {
+				Expect(obj.GetObjectKind().GroupVersionKind().Kind).NotTo(Equal("Gateway"))
+			}
+		})
+	})
+
+	Context("When the health check HTTPRoute is enabled", func() {
+		It("should send only /health straight to the model server Service", func() {
+			infScheduler.Spec.ModelServer.Port = 8000
+			infScheduler.Spec.Gateway.HealthCheckRoute = true
+
+			r := &InferenceSchedulerReconciler{}
+			route := r.buildHealthCheckHTTPRoute(infScheduler)
+			Expect(route.GetName()).To(Equal("test-scheduler-health-route"))
+
+			rules, _, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", "rules")
+			Expect(rules).To(HaveLen(1))
+			rule := rules.([]interface{})[0].(map[string]interface{})
+			Expect(rule["matches"]).To(ConsistOf(map[string]interface{}{
+				"path": map[string]interface{}{"type": "Exact", "value": "/health"},
+			}))
+			Expect(rule["backendRefs"]).To(ConsistOf(map[string]interface{}{
+				"kind": "Service",
+				"name": "test-scheduler-vllm",
+				"port": int32(8000),
+			}))
+
+			// The main route keeps sending everything else to the InferencePool
+			mainRules, _, _ := unstructured.NestedFieldNoCopy(r.buildHTTPRoute(infScheduler).Object, "spec", "rules")
+			Expect(mainRules).NotTo(BeEmpty())
+			for _, mainRule := range mainRules.([]interface{}) {
+				for _, backend := range mainRule.(map[string]interface{})["backendRefs"].([]interface{}) {
+					Expect(backend).To(HaveKeyWithValue("kind", "InferencePool"))
+				}
+			}
+		})
+
+		It("should attach to the Gateway the operator creates", func() {
+			r := &InferenceSchedulerReconciler{}
+			parentRefs, _, _ := unstructured.NestedFieldNoCopy(r.buildHealthCheckHTTPRoute(infScheduler).Object, "spec", "parentRefs")
+			Expect(parentRefs).To(ConsistOf(map[string]interface{}{
+				"name":      "test-scheduler-gateway",
+				"namespace": "default",
+			}))
+		})
+
+		It("should attach to the referenced Gateway", func() {
+			infScheduler.Spec.Gateway.ExistingGatewayRef = &llmv1alpha1.GatewayReference{Name: "shared", Namespace: "gateways"}
+			infScheduler.Spec.Gateway.SectionName = "inference"
+
+			r := &InferenceSchedulerReconciler{}
+			parentRefs, _, _ := unstructured.NestedFieldNoCopy(r.buildHealthCheckHTTPRoute(infScheduler).Object, "spec", "parentRefs")
+			Expect(parentRefs).To(ConsistOf(map[string]interface{}{
+				"name":        "shared",
+				"namespace":   "gateways",
+				"sectionName": "inference",
+			}))
+		})
+	})
+
+	Context("When Gateway listeners conflict", func() {
+		listener := func(name, protocol string, port int64) interface{} {
+			return map[string]interface{}{"name": name, "protocol": protocol, "port": port}
+		}
+
+		It("should accept the listener the operator builds", func() {
+			r := &InferenceSchedulerReconciler{}
+			listeners, _, _ := unstructured.NestedFieldNoCopy(r.buildGateway(infScheduler).Object, "spec", "listeners")
+			Expect(validateGatewayListeners(listeners.([]interface{}))).To(BeEmpty())
+		})
+
+		It("should report duplicate names and ports", func() {
+			Expect(validateGatewayListeners([]interface{}{
+				listener("http", "HTTP", 80),
+				listener("http", "HTTP", 8080),
+			})).To(ConsistOf(ContainSubstring(`listener name "http"`)))
+
+			Expect(validateGatewayListeners([]interface{}{
+				listener("http", "HTTP", 80),
+				listener("tls", "HTTPS", 80),
+			})).To(ConsistOf(ContainSubstring("serves HTTPS on port 80, which already serves HTTP")))
+
+			Expect(validateGatewayListeners([]interface{}{
+				listener("http", "HTTP", 80),
+				listener("other", "HTTP", 80),
+			})).To(ConsistOf(ContainSubstring(`"http" and "other" both serve HTTP on port 80`)))
+		})
+
+		It("should allow listeners on one port with different hostnames", func() {
+			other := listener("other", "HTTP", 80).(map[string]interface{})
+			other["hostname"] = "other.example.com"
+			Expect(validateGatewayListeners([]interface{}{listener("http", "HTTP", 80), other})).To(BeEmpty())
+		})
+
+		It("should check the listeners of an existing Gateway it does not control", func() {
+			infScheduler.UID = "test-uid"
+			infScheduler.Spec.Gateway.ListenerPort = 80
+			gatewayGVK := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+			scheme := runtime.NewScheme()
+			Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+			scheme.AddKnownTypeWithName(gatewayGVK, &unstructured.Unstructured{})
+
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(gatewayGVK)
+			existing.SetName("test-scheduler-gateway")
+			existing.SetNamespace("default")
+			Expect(unstructured.SetNestedSlice(existing.Object, []interface{}{listener("https", "HTTPS", 80)}, "spec", "listeners")).To(Succeed())
+
+			r := &InferenceSchedulerReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build(), Scheme: scheme}
+			conflicts, err := r.gatewayListenerConflicts(context.Background(), r.buildGateway(infScheduler), infScheduler)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(ConsistOf(ContainSubstring("serves HTTP on port 80, which already serves HTTPS")))
+
+			// The operator replaces the listeners of its own Gateway
+			Expect(controllerutil.SetControllerReference(infScheduler, existing, scheme)).To(Succeed())
+			r.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+			conflicts, err = r.gatewayListenerConflicts(context.Background(), r.buildGateway(infScheduler), infScheduler)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(BeEmpty())
+		})
+	})
+
+	Context("When children opt out of drift reversion", func() {
+		var r *InferenceSchedulerReconciler
+
+		BeforeEach(func() {
+			r = &InferenceSchedulerReconciler{}
+		})
+
+		It("should leave a child that opts out entirely alone", func() {
+			desired := r.buildModelServerDeployment(infScheduler)
+			existing := desired.DeepCopy()
+			existing.Annotations = map[string]string{ignoreDriftAnnotation: "true"}
+
+			skip, err := keepIgnoredDrift(desired, existing)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skip).To(BeTrue())
+		})
+
+		It("should keep the live values of the opted out fields", func() {
+			desired := r.buildModelServerDeployment(infScheduler)
+			setManagedMetadata(desired)
+			setAppliedHash(desired)
+
+			existing := desired.DeepCopy()
+			existing.Annotations = map[string]string{
+				ignoreDriftAnnotation:   "spec.template.metadata.annotations, metadata.annotations",
+				"mesh.example.com/mode": "sidecar",
+			}
+			existing.Spec.Template.Annotations = map[string]string{"sidecar.istio.io/inject": "true"}
+			existing.Spec.Template.Spec.Containers[0].Image = "vllm/vllm-openai:edited"
+
+			skip, err := keepIgnoredDrift(desired, existing)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skip).To(BeFalse())
+			Expect(desired.Spec.Template.Annotations).To(HaveKeyWithValue("sidecar.istio.io/inject", "true"))
+			Expect(desired.Annotations).To(HaveKeyWithValue("mesh.example.com/mode", "sidecar"))
+			Expect(desired.Annotations).To(HaveKey(appliedHashAnnotation))
+			Expect(desired.Annotations).To(HaveKeyWithValue(ignoreDriftAnnotation, existing.Annotations[ignoreDriftAnnotation]))
+			Expect(desired.Labels).To(HaveKeyWithValue(managedByLabel, managedByValue))
+
+			// Fields that are not opted out are still reverted
+			Expect(desired.Spec.Template.Spec.Containers[0].Image).NotTo(Equal("vllm/vllm-openai:edited"))
+		})
+
+		It("should keep opted out fields of unstructured children", func() {
+			infScheduler.Spec.Gateway.ListenerPort = 80
+			desired := r.buildGateway(infScheduler)
+
+			content, err := comparableContent(desired)
+			Expect(err).NotTo(HaveOccurred())
+			existing := &unstructured.Unstructured{Object: content}
+			existing.SetAnnotations(map[string]string{ignoreDriftAnnotation: "spec.infrastructure"})
+			Expect(unstructured.SetNestedField(existing.Object, "mesh", "spec", "infrastructure", "labels", "istio.io/dataplane-mode")).To(Succeed())
+
+			skip, err := keepIgnoredDrift(desired, existing)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skip).To(BeFalse())
+			Expect(desired.Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("infrastructure",
+				HaveKeyWithValue("labels", HaveKeyWithValue("istio.io/dataplane-mode", "mesh")))))
+		})
+	})
+
+	Context("When EPP observability is configured", func() {
+		It("should not configure an exporter by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			Expect(r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Env).To(BeEmpty())
+		})
+
+		It("should export to the OTLP endpoint", func() {
+			infScheduler.Spec.EndpointPicker.Observability = &llmv1alpha1.EPPObservabilitySpec{
+				OTLPEndpoint: "http://otel-collector.observability:4317",
+				HeadersSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "otel-auth"},
+					Key:                  "headers",
+				},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			env := r.buildEPPDeployment(infScheduler).Spec.Template.Spec.Containers[0].Env
+			Expect(env).To(ContainElements(
+				corev1.EnvVar{Name: "OTEL_SERVICE_NAME", Value: "test-scheduler-epp"},
+				corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector.observability:4317"},
+				corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "grpc"},
+			))
+			Expect(env).To(ContainElement(And(
+				HaveField("Name", "OTEL_EXPORTER_OTLP_HEADERS"),
+				HaveField("ValueFrom.SecretKeyRef.Name", "otel-auth"),
+				HaveField("ValueFrom.SecretKeyRef.Key", "headers"),
+			)))
+		})
+	})
+
+	Context("When the model server Service port is configured", func() {
+		It("should name the port http by default", func() {
+			r := &InferenceSchedulerReconciler{}
+			servicePort := r.buildModelServerService(infScheduler).Spec.Ports[0]
+			Expect(servicePort.Name).To(Equal("http"))
+			Expect(servicePort.AppProtocol).To(BeNil())
+		})
+
+		It("should set the configured name and appProtocol", func() {
+			appProtocol := "http"
+			infScheduler.Spec.ModelServer.Service = &llmv1alpha1.ModelServerServiceSpec{PortName: "http-vllm", AppProtocol: &appProtocol}
+
+			r := &InferenceSchedulerReconciler{}
+			servicePort := r.buildModelServerService(infScheduler).Spec.Ports[0]
+			Expect(servicePort.Name).To(Equal("http-vllm"))
+			Expect(*servicePort.AppProtocol).To(Equal("http"))
+		})
+	})
+
+	Context("When the model server has extra args and env", func() {
+		It("should append extra args after the generated ones", func() {
+			infScheduler.Spec.ModelServer.ExtraArgs = []string{"--max-model-len=8192", "--dtype", "bfloat16"}
+
+			r := &InferenceSchedulerReconciler{}
+			args := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Args
+			Expect(args[0]).To(HavePrefix("--model="))
+			Expect(args[len(args)-3:]).To(Equal([]string{"--max-model-len=8192", "--dtype", "bfloat16"}))
+		})
+
+		It("should drop generated flags that extra args set again", func() {
+			infScheduler.Spec.ModelServer.ServedModelNames = []string{"qwen", "default"}
+			infScheduler.Spec.ModelServer.ExtraArgs = []string{"--gpu-memory-utilization", "0.5", "--served-model-name=other"}
+
+			r := &InferenceSchedulerReconciler{}
+			args := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Args
+			Expect(args).To(Equal([]string{
+				"--model=Qwen/Qwen2.5-0.5B-Instruct",
+				"--port=0",
+				"--gpu-memory-utilization", "0.5", "--served-model-name=other",
+			}))
+		})
+
+		It("should merge extra env into the generated env", func() {
+			infScheduler.Spec.ModelServer.ExtraEnv = []corev1.EnvVar{
+				{Name: "VLLM_ATTENTION_BACKEND", Value: "FLASHINFER"},
+				{Name: "HF_TOKEN", Value: "inline"},
+			}
+
+			r := &InferenceSchedulerReconciler{}
+			env := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0].Env
+			Expect(env[0]).To(Equal(corev1.EnvVar{Name: "HF_TOKEN", Value: "inline"}))
+			Expect(env[len(env)-1]).To(Equal(corev1.EnvVar{Name: "VLLM_ATTENTION_BACKEND", Value: "FLASHINFER"}))
+		})
+	})
+
+	Context("When tensor parallelism is configured", func() {
+		It("should request one GPU per shard", func() {
+			tensorParallelSize := int32(4)
+			infScheduler.Spec.ModelServer.TensorParallelSize = &tensorParallelSize
+
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.Args).To(ContainElement("--tensor-parallel-size=4"))
+			gpuLimit := vllm.Resources.Limits[gpuResourceName]
+			Expect(gpuLimit.Value()).To(Equal(int64(4)))
+			Expect(vllm.Resources.Limits).To(HaveLen(1))
+		})
+
+		It("should keep an explicit GPU resource", func() {
+			tensorParallelSize := int32(4)
+			infScheduler.Spec.ModelServer.TensorParallelSize = &tensorParallelSize
+			infScheduler.Spec.ModelServer.Resources.Limits = corev1.ResourceList{gpuResourceName: resource.MustParse("8")}
+
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.Args).To(ContainElement("--tensor-parallel-size=4"))
+			Expect(vllm.Resources.Limits[gpuResourceName]).To(Equal(resource.MustParse("8")))
+		})
+
+		It("should not request GPUs without tensor parallelism", func() {
+			r := &InferenceSchedulerReconciler{}
+			vllm := r.buildModelServerDeployment(infScheduler).Spec.Template.Spec.Containers[0]
+			Expect(vllm.Args).NotTo(ContainElement(HavePrefix("--tensor-parallel-size")))
+			Expect(vllm.Resources.Limits).NotTo(HaveKey(gpuResourceName))
+		})
+	})
+
+	Context("When PodDisruptionBudgets are configured", func() {
+		It("should select the model server and EPP pods", func() {
+			infScheduler.Spec.ModelServer.Replicas = 3
+			infScheduler.Spec.ModelServer.PodDisruptionBudget = &llmv1alpha1.PodDisruptionBudgetSpec{}
+			infScheduler.Spec.EndpointPicker.Replicas = 2
+			infScheduler.Spec.EndpointPicker.PodDisruptionBudget = &llmv1alpha1.PodDisruptionBudgetSpec{}
+
+			r := &InferenceSchedulerReconciler{}
+			modelServerPDB := r.buildModelServerPDB(infScheduler)
+			Expect(modelServerPDB.Name).To(Equal("test-scheduler-vllm"))
+			Expect(*modelServerPDB.Spec.MaxUnavailable).To(Equal(intstr.FromInt32(1)))
+			selector, err := metav1.LabelSelectorAsSelector(modelServerPDB.Spec.Selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(labels.Set(r.buildModelServerDeployment(infScheduler).Spec.Template.Labels))).To(BeTrue())
+			Expect(selector.Matches(labels.Set(r.buildEPPDeployment(infScheduler).Spec.Template.Labels))).To(BeFalse())
+
+			infScheduler.Spec.ModelServer.Workload = workloadStatefulSet
+			Expect(selector.Matches(labels.Set(r.buildModelServerWorkload(infScheduler).(*appsv1.StatefulSet).Spec.Template.Labels))).To(BeTrue())
+
+			eppPDB := r.buildEPPPDB(infScheduler)
+			Expect(eppPDB.Name).To(Equal("test-scheduler-epp"))
+			selector, err = metav1.LabelSelectorAsSelector(eppPDB.Spec.Selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(labels.Set(r.buildEPPDeployment(infScheduler).Spec.Template.Labels))).To(BeTrue())
+		})
+
+		It("should use the configured budget", func() {
+			minAvailable := intstr.FromString("50%")
+			infScheduler.Spec.ModelServer.PodDisruptionBudget = &llmv1alpha1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable}
+
+			r := &InferenceSchedulerReconciler{}
+			pdb := r.buildModelServerPDB(infScheduler)
+			Expect(pdb.Spec.MinAvailable).To(Equal(&minAvailable))
+			Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+
+			maxUnavailable := intstr.FromInt32(2)
+			infScheduler.Spec.ModelServer.PodDisruptionBudget.MaxUnavailable = &maxUnavailable
+			Expect(validatePodDisruptionBudgets(infScheduler.Spec)).To(ConsistOf(ContainSubstring("modelServer.podDisruptionBudget")))
+		})
+
+		It("should not create a default budget for a single replica or without opting in", func() {
+			r := &InferenceSchedulerReconciler{}
+			infScheduler.Spec.ModelServer.Replicas = 3
+			Expect(r.buildModelServerPDB(infScheduler)).To(BeNil())
+
+			infScheduler.Spec.ModelServer.Replicas = 1
+			infScheduler.Spec.ModelServer.PodDisruptionBudget = &llmv1alpha1.PodDisruptionBudgetSpec{}
+			Expect(r.buildModelServerPDB(infScheduler)).To(BeNil())
+
+			// An autoscaled model server is budgeted for its maximum
+			infScheduler.Spec.ModelServer.Autoscaling = &llmv1alpha1.AutoscalingSpec{MaxReplicas: 4}
+			Expect(r.buildModelServerPDB(infScheduler)).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,395 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// hfRepoNamePattern matches the owner or the name of a HuggingFace repo id: letters, digits, '-',
+// '_' and '.', not starting or ending with '-' or '.'
+var hfRepoNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9._-]*[A-Za-z0-9_])?$`)
+
+// validateModelName returns an error if name is neither a plausible HuggingFace repo id, such as
+// org/model, nor an absolute path to a model in the container. vLLM only reports such names once
+// its pod is running, as a crash loop
+func validateModelName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("model name is empty")
+	}
+	if strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("model name %q contains whitespace", name)
+	}
+	if strings.HasPrefix(name, "/") {
+		if path.Clean(name) == "/" {
+			return fmt.Errorf("model path %q is the root directory", name)
+		}
+		return nil
+	}
+
+	parts := strings.Split(name, "/")
+	if len(parts) > 2 {
+		return fmt.Errorf("model name %q must be a HuggingFace repo id like org/model or an absolute path", name)
+	}
+	for _, part := range parts {
+		if len(part) > 96 || !hfRepoNamePattern.MatchString(part) || strings.Contains(part, "--") || strings.Contains(part, "..") {
+			return fmt.Errorf("model name %q is not a valid HuggingFace repo id: each part may only contain letters, digits, "+
+				"'-', '_' and '.', must not start or end with '-' or '.', and is at most 96 characters", name)
+		}
+	}
+	return nil
+}
+
+// validateModelNames returns an error for every model server whose model name is not valid
+func validateModelNames(spec llmv1alpha1.InferenceSchedulerSpec) []string {
+	var errs []string
+	if err := validateModelName(spec.ModelServer.ModelName); err != nil {
+		errs = append(errs, fmt.Sprintf("modelServer: %v", err))
+	}
+	for _, extra := range spec.ExtraModelServers {
+		if err := validateModelName(extra.ModelName); err != nil {
+			errs = append(errs, fmt.Sprintf("extra model server %s: %v", extra.Name, err))
+		}
+	}
+	return errs
+}
+
+// scorerEnabled reports whether a scorer plugin is configured and enabled
+func scorerEnabled(scorer *llmv1alpha1.ScorerPlugin) bool {
+	return scorer != nil && scorer.Enabled
+}
+
+// validatePluginConfig returns warnings for scorer combinations that are accepted by the EPP
+// but are likely to produce poor routing decisions
+func validatePluginConfig(plugins llmv1alpha1.PluginConfig) []string {
+	var warnings []string
+
+	// Both scorers favour the least loaded endpoint, so together they double count load
+	if scorerEnabled(plugins.LoadAwareScorer) && scorerEnabled(plugins.KVCacheUtilizationScorer) {
+		warnings = append(warnings, "loadAwareScorer and kvCacheUtilizationScorer both score endpoint load; "+
+			"enabling both over-weights load relative to prefix cache affinity")
+	}
+
+	// Weights default to the values used when rendering plugins.yaml
+	enabled := 0
+	totalWeight := 0.0
+	if scorerEnabled(plugins.LoadAwareScorer) {
+		enabled++
+		totalWeight += getDefaultFloat64(plugins.LoadAwareScorer.Weight, 1.0)
+	}
+	if scorerEnabled(plugins.PrefixCacheScorer) {
+		enabled++
+		totalWeight += getDefaultFloat64(plugins.PrefixCacheScorer.Weight, 2.0)
+	}
+	if scorerEnabled(plugins.KVCacheUtilizationScorer) {
+		enabled++
+		totalWeight += getDefaultFloat64(plugins.KVCacheUtilizationScorer.Weight, 1.0)
+	}
+	if scorerEnabled(plugins.SessionAffinityScorer) {
+		enabled++
+		totalWeight += getDefaultFloat64(plugins.SessionAffinityScorer.Weight, 1.0)
+	}
+	if scorerEnabled(plugins.LoraAffinityScorer) {
+		enabled++
+		totalWeight += getDefaultFloat64(plugins.LoraAffinityScorer.Weight, 1.0)
+	}
+	for _, scorer := range plugins.AdditionalScorers {
+		enabled++
+		totalWeight += getDefaultFloat64(scorer.Weight, 1.0)
+	}
+	if enabled > 0 && totalWeight == 0 {
+		warnings = append(warnings, "the total weight of all enabled scorers is 0, which disables scoring entirely")
+	}
+
+	return warnings
+}
+
+// gpuResourceName is the extended resource exposed by the NVIDIA device plugin
+const gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+// validateSidecars returns warnings for model server sidecars that request GPUs. Each GPU is
+// exclusively allocated to one container, so a GPU on a sidecar is usually taken from vLLM by mistake
+func validateSidecars(sidecars []corev1.Container) []string {
+	var warnings []string
+	for _, sidecar := range sidecars {
+		_, inRequests := sidecar.Resources.Requests[gpuResourceName]
+		_, inLimits := sidecar.Resources.Limits[gpuResourceName]
+		if inRequests || inLimits {
+			warnings = append(warnings, fmt.Sprintf("sidecar %q requests %s; GPUs should only be assigned to the model server container",
+				sidecar.Name, gpuResourceName))
+		}
+	}
+	return warnings
+}
+
+// validateTokenSecrets returns errors for additional token secrets whose environment variable
+// names collide with HF_TOKEN or with each other
+func validateTokenSecrets(tokens []llmv1alpha1.TokenSecretRef) []string {
+	var errs []string
+	seen := map[string]bool{"HF_TOKEN": true}
+	for _, token := range tokens {
+		if seen[token.EnvName] {
+			errs = append(errs, fmt.Sprintf("environment variable %s is set more than once", token.EnvName))
+		}
+		seen[token.EnvName] = true
+	}
+	return errs
+}
+
+// hfAuthFailureMarkers are fragments of the huggingface_hub errors vLLM logs when the token is
+// rejected or lacks access to a gated model
+var hfAuthFailureMarkers = []string{
+	"401 Client Error",
+	"Invalid user token",
+	"GatedRepoError",
+}
+
+// isHFAuthFailure reports whether a model server termination message shows that the HuggingFace
+// download failed because of the token
+func isHFAuthFailure(message string) bool {
+	for _, marker := range hfAuthFailureMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateExtraModelServers returns errors for extra model servers that would share pods with
+// another model server or be left out of the InferencePool
+func validateExtraModelServers(spec llmv1alpha1.InferenceSchedulerSpec) []string {
+	if len(spec.ExtraModelServers) == 0 {
+		return nil
+	}
+
+	var errs []string
+	// The workloads select on the model, so two of them serving one model select each other's pods
+	models := map[string]string{sanitizeName(spec.ModelServer.ModelName): "modelServer"}
+	names := map[string]bool{}
+	for _, extra := range spec.ExtraModelServers {
+		if names[extra.Name] {
+			errs = append(errs, fmt.Sprintf("extra model server %s is defined twice", extra.Name))
+			continue
+		}
+		names[extra.Name] = true
+		model := sanitizeName(extra.ModelName)
+		if other, ok := models[model]; ok {
+			errs = append(errs, fmt.Sprintf("extra model server %s serves model %s, which %s already serves", extra.Name, extra.ModelName, other))
+			continue
+		}
+		models[model] = fmt.Sprintf("extra model server %s", extra.Name)
+	}
+	if spec.ModelServer.PinnedRevision != "" {
+		errs = append(errs, "pinnedRevision cannot be set with extraModelServers, the InferencePool would only select the primary model server")
+	}
+	return errs
+}
+
+// validateEPPPorts returns errors for EPP ports that are used twice, since the EPP cannot bind
+// two servers to one port
+func validateEPPPorts(epp llmv1alpha1.EndpointPickerSpec) []string {
+	grpcPort := getDefaultInt32(&epp.GRPCPort, defaultEPPGRPCPort)
+	healthPort := getDefaultInt32(epp.HealthPort, defaultEPPHealthPort)
+	metricsPort := getDefaultInt32(epp.MetricsPort, defaultEPPMetricsPort)
+
+	var errs []string
+	if healthPort == grpcPort {
+		errs = append(errs, fmt.Sprintf("port %d is used for both the gRPC and health ports", grpcPort))
+	}
+	if metricsPort == grpcPort {
+		errs = append(errs, fmt.Sprintf("port %d is used for both the gRPC and metrics ports", grpcPort))
+	}
+	if metricsPort == healthPort {
+		errs = append(errs, fmt.Sprintf("port %d is used for both the health and metrics ports", healthPort))
+	}
+	return errs
+}
+
+// validateEPPNodePort returns errors for EPP node ports that the API server would reject or
+// that would be silently dropped. The allowed range is enforced by the CRD schema
+func validateEPPNodePort(epp llmv1alpha1.EndpointPickerSpec) []string {
+	nodePort := epp.NodePort
+	if nodePort == nil {
+		return nil
+	}
+
+	var errs []string
+	if nodePort.GRPC != nil && nodePort.Health != nil && *nodePort.GRPC == *nodePort.Health {
+		errs = append(errs, fmt.Sprintf("node port %d is used for both the gRPC and health ports", *nodePort.GRPC))
+	}
+	if nodePort.Health != nil && !getDefaultBool(epp.ExposeHealthPort, true) {
+		errs = append(errs, "a health node port is set but exposeHealthPort is disabled")
+	}
+	return errs
+}
+
+// validateModelServerService returns an error for a model server node port that the Service type
+// would not use
+func validateModelServerService(spec *llmv1alpha1.ModelServerServiceSpec) error {
+	serviceType := getDefaultString(spec.Type, string(corev1.ServiceTypeClusterIP))
+	if spec.NodePort != nil && serviceType == string(corev1.ServiceTypeClusterIP) {
+		return fmt.Errorf("node port %d requires a NodePort or LoadBalancer service type, not %s", *spec.NodePort, serviceType)
+	}
+	return nil
+}
+
+// validateModelServerShutdown returns an error if the preStop sleep does not end before the
+// grace period, since the kubelet kills the container once the grace period is over
+func validateModelServerShutdown(modelServer llmv1alpha1.ModelServerSpec) error {
+	if modelServer.PreStopSleepSeconds == nil {
+		return nil
+	}
+	gracePeriod := modelServerTerminationGracePeriod(modelServer)
+	if int64(*modelServer.PreStopSleepSeconds) >= gracePeriod {
+		return fmt.Errorf("preStopSleepSeconds (%d) must be less than terminationGracePeriodSeconds (%d)",
+			*modelServer.PreStopSleepSeconds, gracePeriod)
+	}
+	return nil
+}
+
+// validatePodDisruptionBudgets returns the PodDisruptionBudgets that set both minAvailable and
+// maxUnavailable, which policy/v1 does not allow
+func validatePodDisruptionBudgets(spec llmv1alpha1.InferenceSchedulerSpec) []string {
+	var errs []string
+	if pdb := spec.ModelServer.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		errs = append(errs, "modelServer.podDisruptionBudget sets both minAvailable and maxUnavailable")
+	}
+	if pdb := spec.EndpointPicker.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		errs = append(errs, "endpointPicker.podDisruptionBudget sets both minAvailable and maxUnavailable")
+	}
+	return errs
+}
+
+// validateExistingGateway returns the settings that only apply to a Gateway the operator creates
+// but are set together with ExistingGatewayRef
+func validateExistingGateway(gateway llmv1alpha1.GatewaySpec) []string {
+	if gateway.ExistingGatewayRef == nil {
+		return nil
+	}
+
+	var errs []string
+	// className is defaulted by the API server, so the default cannot be told apart from unset
+	if getDefaultString(gateway.ClassName, "kgateway") != "kgateway" {
+		errs = append(errs, "className cannot be set with existingGatewayRef")
+	}
+	if gateway.TLS != nil {
+		errs = append(errs, "tls cannot be set with existingGatewayRef, configure TLS on the existing Gateway")
+	}
+	if gateway.AllowedRoutes != nil {
+		errs = append(errs, "allowedRoutes cannot be set with existingGatewayRef, configure them on the existing Gateway")
+	}
+	return errs
+}
+
+// validateGatewayListeners returns the conflicts between Gateway listeners that would keep the
+// Gateway from being programmed: duplicate names, one port serving different protocols, and the
+// same port, protocol and hostname served twice
+func validateGatewayListeners(listeners []interface{}) []string {
+	var conflicts []string
+	names := map[string]bool{}
+	protocols := map[int64]string{}
+	hostnames := map[string]string{}
+
+	for _, l := range listeners {
+		listener, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(listener, "name")
+		protocol, _, _ := unstructured.NestedString(listener, "protocol")
+		hostname, _, _ := unstructured.NestedString(listener, "hostname")
+		port, ok := listenerPort(listener["port"])
+		if !ok {
+			continue
+		}
+
+		if names[name] {
+			conflicts = append(conflicts, fmt.Sprintf("listener name %q is used more than once", name))
+		}
+		names[name] = true
+
+		if existing, found := protocols[port]; found && existing != protocol {
+			conflicts = append(conflicts, fmt.Sprintf("listener %q serves %s on port %d, which already serves %s", name, protocol, port, existing))
+			continue
+		}
+		protocols[port] = protocol
+
+		key := fmt.Sprintf("%d/%s/%s", port, protocol, hostname)
+		if existing, found := hostnames[key]; found {
+			conflicts = append(conflicts, fmt.Sprintf("listeners %q and %q both serve %s on port %d for hostname %q", existing, name, protocol, port, hostname))
+			continue
+		}
+		hostnames[key] = name
+	}
+	return conflicts
+}
+
+// listenerPort reads a listener port, which is an int32 when built by the operator and an int64
+// when read from the API server
+func listenerPort(value interface{}) (int64, bool) {
+	switch port := value.(type) {
+	case int32:
+		return int64(port), true
+	case int64:
+		return port, true
+	case float64:
+		return int64(port), true
+	}
+	return 0, false
+}
+
+// validateModelCache returns an error unless the model cache sets exactly one volume source, and
+// for a provisioned claim that cannot be shared when the model server may run more than one replica
+func validateModelCache(cache *llmv1alpha1.ModelCacheSpec, maxReplicas int32) error {
+	if cache == nil {
+		return nil
+	}
+
+	sources := 0
+	if cache.Claim != nil {
+		sources++
+	}
+	if cache.PersistentVolumeClaim != nil {
+		sources++
+	}
+	if cache.NFS != nil {
+		sources++
+	}
+	if cache.CSI != nil {
+		sources++
+	}
+	if sources != 1 {
+		return fmt.Errorf("modelCache must set exactly one of claim, persistentVolumeClaim, nfs or csi, found %d", sources)
+	}
+
+	if cache.Claim != nil && maxReplicas > 1 {
+		accessMode := cache.Claim.AccessMode
+		if accessMode == corev1.ReadWriteOnce || accessMode == corev1.ReadWriteOncePod {
+			return fmt.Errorf("modelCache claim accessMode %s cannot be shared by up to %d model server replicas, use ReadWriteMany",
+				accessMode, maxReplicas)
+		}
+	}
+	return nil
+}
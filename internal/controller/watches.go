@@ -0,0 +1,121 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// childWatches registers watches on the unstructured child kinds once their CRDs are installed.
+// The Gateway API and GIE CRDs may be installed after the operator starts, so kinds that are
+// missing at startup are retried on every reconcile that passes prerequisite validation.
+type childWatches struct {
+	mu         sync.Mutex
+	mgr        ctrl.Manager
+	controller controller.Controller
+	watched    map[schema.GroupVersionKind]bool
+}
+
+// ensure starts a watch for every unstructured child kind that is not watched yet and whose
+// CRD is now served by the API server
+func (w *childWatches) ensure(ctx context.Context) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	logger := log.FromContext(ctx)
+	for _, gvk := range unstructuredChildGVKs {
+		if w.watched[gvk] {
+			continue
+		}
+		if _, err := w.mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		src := source.Kind(w.mgr.GetCache(), obj,
+			handler.TypedEnqueueRequestForOwner[*unstructured.Unstructured](
+				w.mgr.GetScheme(), w.mgr.GetRESTMapper(), &llmv1alpha1.InferenceScheduler{}, handler.OnlyControllerOwner()))
+		if err := w.controller.Watch(src); err != nil {
+			logger.Error(err, "Failed to watch kind", "kind", gvk.String())
+			continue
+		}
+
+		logger.Info("Started watching kind", "kind", gvk.String())
+		w.watched[gvk] = true
+	}
+}
+
+// prerequisiteCRDGroups are the API groups of the CRDs checked by validatePrerequisites
+var prerequisiteCRDGroups = []string{"gateway.networking.k8s.io", "inference.networking.k8s.io"}
+
+// crdMetadata returns the object to watch CRDs by their metadata only, which avoids caching
+// their large schemas
+func crdMetadata() *metav1.PartialObjectMetadata {
+	crd := &metav1.PartialObjectMetadata{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+	return crd
+}
+
+// findSchedulersForCRD enqueues the InferenceSchedulers waiting for their prerequisites when a
+// Gateway API or GIE CRD is installed, instead of leaving them to the next recheck
+func (r *InferenceSchedulerReconciler) findSchedulersForCRD(ctx context.Context, obj client.Object) []reconcile.Request {
+	// CRDs are named <plural>.<group>
+	prerequisite := false
+	for _, group := range prerequisiteCRDGroups {
+		if strings.HasSuffix(obj.GetName(), "."+group) {
+			prerequisite = true
+			break
+		}
+	}
+	if !prerequisite {
+		return nil
+	}
+
+	schedulers := &llmv1alpha1.InferenceSchedulerList{}
+	if err := r.List(ctx, schedulers); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list InferenceSchedulers for CRD", "crd", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, infScheduler := range schedulers.Items {
+		if !infScheduler.Status.PrerequisitesValidated {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&infScheduler)})
+		}
+	}
+	return requests
+}
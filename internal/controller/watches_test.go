@@ -0,0 +1,201 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// restMapperManager is a manager that only serves a scheme and a RESTMapper
+type restMapperManager struct {
+	manager.Manager
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+}
+
+func (m *restMapperManager) GetScheme() *runtime.Scheme     { return m.scheme }
+func (m *restMapperManager) GetRESTMapper() meta.RESTMapper { return m.mapper }
+func (m *restMapperManager) GetCache() cache.Cache          { return nil }
+
+// watchRecorder is a controller that records the sources it is asked to watch
+type watchRecorder struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (c *watchRecorder) Watch(src source.Source) error {
+	c.sources = append(c.sources, src)
+	return nil
+}
+
+var _ = Describe("Child watches", func() {
+	var (
+		scheme       *runtime.Scheme
+		infScheduler *llmv1alpha1.InferenceScheduler
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(llmv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		infScheduler = &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-scheduler", Namespace: "default", UID: "1234"},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{
+					ModelName:         "Qwen/Qwen2.5-0.5B-Instruct",
+					Replicas:          2,
+					HFTokenSecretName: "hf-token",
+				},
+			},
+		}
+	})
+
+	// enqueued runs a create event for obj through h and returns the requests it queued
+	enqueued := func(h handler.EventHandler, obj client.Object) []reconcile.Request {
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer queue.ShutDown()
+
+		h.Create(context.Background(), event.CreateEvent{Object: obj}, queue)
+		var requests []reconcile.Request
+		for queue.Len() > 0 {
+			request, _ := queue.Get()
+			requests = append(requests, request)
+			queue.Done(request)
+		}
+		return requests
+	}
+
+	It("should watch every kind the operator creates", func() {
+		watched := map[schema.GroupKind]bool{}
+		for _, kind := range typedChildKinds {
+			gvk, err := apiutil.GVKForObject(kind, scheme)
+			Expect(err).NotTo(HaveOccurred())
+			watched[gvk.GroupKind()] = true
+		}
+		for _, gvk := range unstructuredChildGVKs {
+			watched[gvk.GroupKind()] = true
+		}
+
+		// Every optional child enabled, in both workload flavors
+		infScheduler.Spec.ModelServer.LogFormat = logFormatJSON
+		infScheduler.Spec.ModelServer.ModelCache = &llmv1alpha1.ModelCacheSpec{
+			Claim: &llmv1alpha1.ModelCacheClaimSpec{Size: resource.MustParse("10Gi")},
+		}
+		infScheduler.Spec.ModelServer.Autoscaling = &llmv1alpha1.AutoscalingSpec{MaxReplicas: 4}
+		minAvailable := intstr.FromInt32(1)
+		infScheduler.Spec.ModelServer.PodDisruptionBudget = &llmv1alpha1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable}
+		infScheduler.Spec.Gateway.HealthCheckRoute = true
+		infScheduler.Spec.Monitoring = &llmv1alpha1.MonitoringSpec{PrometheusRule: true, ServiceMonitor: true, Dashboard: true}
+		statefulSet := infScheduler.DeepCopy()
+		statefulSet.Spec.ModelServer.Workload = workloadStatefulSet
+
+		created := map[schema.GroupKind]bool{}
+		for _, obj := range append(BuildChildObjects(infScheduler), BuildChildObjects(statefulSet)...) {
+			gvk, err := apiutil.GVKForObject(obj, scheme)
+			Expect(err).NotTo(HaveOccurred())
+			created[gvk.GroupKind()] = true
+		}
+		for kind := range created {
+			Expect(watched).To(HaveKey(kind))
+		}
+		// A watched kind the operator never creates would only cost memory
+		Expect(watched).To(HaveLen(len(created)))
+
+		// The orphan cleanup lists the same kinds
+		Expect(childLists()).To(HaveLen(len(watched)))
+	})
+
+	It("should map a child to the InferenceScheduler that controls it", func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(llmv1alpha1.GroupVersion.WithKind("InferenceScheduler"), meta.RESTScopeNamespace)
+		owner := handler.EnqueueRequestForOwner(scheme, mapper, &llmv1alpha1.InferenceScheduler{}, handler.OnlyControllerOwner())
+
+		r := &InferenceSchedulerReconciler{Scheme: scheme}
+		configMap := r.buildEPPConfigMap(infScheduler)
+		Expect(r.setOwnerReference(infScheduler, configMap)).To(Succeed())
+		Expect(enqueued(owner, configMap)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-scheduler"},
+		}))
+
+		// Children whose reference is not the controller one, by policy, do not trigger a reconcile
+		disabled := false
+		infScheduler.Spec.OwnerReferences = []llmv1alpha1.OwnerReferencePolicy{{Kind: "Service", Controller: &disabled}}
+		service := r.buildEPPService(infScheduler)
+		Expect(r.setOwnerReference(infScheduler, service)).To(Succeed())
+		Expect(enqueued(owner, service)).To(BeEmpty())
+
+		Expect(enqueued(owner, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"}})).To(BeEmpty())
+	})
+	It("should start watching a kind once its CRD is installed", func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(llmv1alpha1.GroupVersion.WithKind("InferenceScheduler"), meta.RESTScopeNamespace)
+		recorder := &watchRecorder{}
+		watches := &childWatches{
+			mgr:        &restMapperManager{scheme: scheme, mapper: mapper},
+			controller: recorder,
+			watched:    map[schema.GroupVersionKind]bool{},
+		}
+		pool := schema.GroupVersionKind{Group: "inference.networking.k8s.io", Version: "v1", Kind: "InferencePool"}
+		route := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+		// Nothing is installed yet
+		watches.ensure(context.Background())
+		Expect(recorder.sources).To(BeEmpty())
+
+		mapper.Add(pool, meta.RESTScopeNamespace)
+		watches.ensure(context.Background())
+		Expect(recorder.sources).To(HaveLen(1))
+		Expect(watches.watched).To(Equal(map[schema.GroupVersionKind]bool{pool: true}))
+
+		// A kind is watched once, however often ensure runs
+		mapper.Add(route, meta.RESTScopeNamespace)
+		watches.ensure(context.Background())
+		watches.ensure(context.Background())
+		Expect(recorder.sources).To(HaveLen(2))
+		Expect(watches.watched).To(Equal(map[schema.GroupVersionKind]bool{pool: true, route: true}))
+	})
+
+	It("should do nothing before SetupWithManager ran", func() {
+		var watches *childWatches
+		Expect(func() { watches.ensure(context.Background()) }).NotTo(Panic())
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation holds the checks of an InferenceScheduler spec that need nothing but the spec,
+// so the admission webhook and the controller apply the same rules
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+// The gateway defaults the controller applies when the spec leaves them unset
+const (
+	defaultGatewayClassName = "kgateway"
+	defaultGatewayPort      = 80
+)
+
+// GatewayExposure returns warnings for gateway listener port and service type settings that do
+// not take effect as the user likely expects. How a Gateway is exposed is decided by its
+// implementation, so these are advisory only. The webhook returns them as admission warnings
+func GatewayExposure(gateway llmv1alpha1.GatewaySpec) []string {
+	var warnings []string
+	className := gateway.ClassName
+	if className == "" {
+		className = defaultGatewayClassName
+	}
+	listenerPort := gateway.ListenerPort
+	if listenerPort == 0 {
+		listenerPort = defaultGatewayPort
+	}
+	serviceType := gateway.ServiceType
+	if serviceType == "" {
+		serviceType = string(corev1.ServiceTypeClusterIP)
+	}
+
+	if strings.HasPrefix(className, "gke-") {
+		// GKE provisions a Google Cloud load balancer instead of a Kubernetes Service
+		if serviceType != string(corev1.ServiceTypeClusterIP) {
+			warnings = append(warnings, fmt.Sprintf("serviceType %s has no effect with GatewayClass %s, which is exposed through a Google Cloud load balancer",
+				serviceType, className))
+		}
+		if listenerPort != 80 && listenerPort != 443 {
+			warnings = append(warnings, fmt.Sprintf("GatewayClass %s only supports listener ports 80 and 443, not %d", className, listenerPort))
+		}
+		return warnings
+	}
+
+	if serviceType != string(corev1.ServiceTypeClusterIP) {
+		warnings = append(warnings, fmt.Sprintf("serviceType %s is not applied to the Service that %s creates for the Gateway; "+
+			"configure it through the implementation's Gateway parameters", serviceType, className))
+	}
+	return warnings
+}
+
+// pluginsConfigured reports whether any typed or additional scorer is configured
+func pluginsConfigured(plugins llmv1alpha1.PluginConfig) bool {
+	return plugins.LoadAwareScorer != nil || plugins.PrefixCacheScorer != nil || plugins.KVCacheUtilizationScorer != nil ||
+		plugins.SessionAffinityScorer != nil || plugins.LoraAffinityScorer != nil || len(plugins.AdditionalScorers) > 0
+}
+
+// PluginConfigSource returns an error if the EPP config is both generated from plugins and read
+// from a ConfigMap, since only one of them can be mounted as plugins.yaml
+func PluginConfigSource(epp llmv1alpha1.EndpointPickerSpec) error {
+	if epp.PluginConfigMapRef != nil && pluginsConfigured(epp.Plugins) {
+		return fmt.Errorf("plugins cannot be set with pluginConfigMapRef")
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+	"github.com/aneeshkp/inference-scheduler-operator/internal/validation"
+)
+
+// log is for logging in this package.
+var inferenceschedulerlog = logf.Log.WithName("inferencescheduler-resource")
+
+// scorerParameters lists the Parameters keys each scorer accepts, with a parser that reports
+// whether a value is valid. Any other key ends up in plugins.yaml where the EPP fails to parse it
+var scorerParameters = map[string]map[string]func(string) error{
+	"loadAwareScorer": {
+		"queueThreshold": parseInt,
+	},
+	"prefixCacheScorer": {
+		"cacheHitBonus": parseFloat,
+	},
+	"kvCacheUtilizationScorer": {},
+	"sessionAffinityScorer":    {},
+	"loraAffinityScorer":       {},
+}
+
+// typedScorerTypes maps the EPP plugin type of every scorer with a typed field to that field, so an
+// additional scorer cannot render the same plugin a second time
+var typedScorerTypes = map[string]string{
+	"load-aware-scorer":           "loadAwareScorer",
+	"prefix-cache-scorer":         "prefixCacheScorer",
+	"kv-cache-utilization-scorer": "kvCacheUtilizationScorer",
+	"session-affinity-scorer":     "sessionAffinityScorer",
+	"lora-affinity-scorer":        "loraAffinityScorer",
+}
+
+func parseInt(value string) error {
+	if _, err := strconv.Atoi(value); err != nil {
+		return fmt.Errorf("must be an integer")
+	}
+	return nil
+}
+
+func parseFloat(value string) error {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return fmt.Errorf("must be a number")
+	}
+	return nil
+}
+
+// SetupInferenceSchedulerWebhookWithManager registers the webhook for InferenceScheduler in the manager.
+func SetupInferenceSchedulerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&llmv1alpha1.InferenceScheduler{}).
+		WithValidator(&InferenceSchedulerCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-llm-llm-d-io-v1alpha1-inferencescheduler,mutating=false,failurePolicy=fail,sideEffects=None,groups=llm.llm-d.io,resources=inferenceschedulers,verbs=create;update,versions=v1alpha1,name=vinferencescheduler-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// InferenceSchedulerCustomValidator rejects InferenceSchedulers whose EPP plugin configuration the
+// EPP cannot load, so the mistake is reported on apply instead of as an EPP crashloop
+type InferenceSchedulerCustomValidator struct{}
+
+var _ webhook.CustomValidator = &InferenceSchedulerCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type InferenceScheduler.
+func (v *InferenceSchedulerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	infScheduler, ok := obj.(*llmv1alpha1.InferenceScheduler)
+	if !ok {
+		return nil, fmt.Errorf("expected an InferenceScheduler object but got %T", obj)
+	}
+	inferenceschedulerlog.Info("Validation for InferenceScheduler upon creation", "name", infScheduler.GetName())
+
+	return gatewayWarnings(infScheduler), validateInferenceScheduler(infScheduler)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type InferenceScheduler.
+func (v *InferenceSchedulerCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	infScheduler, ok := newObj.(*llmv1alpha1.InferenceScheduler)
+	if !ok {
+		return nil, fmt.Errorf("expected an InferenceScheduler object for the newObj but got %T", newObj)
+	}
+	inferenceschedulerlog.Info("Validation for InferenceScheduler upon update", "name", infScheduler.GetName())
+
+	return gatewayWarnings(infScheduler), validateInferenceScheduler(infScheduler)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type InferenceScheduler.
+func (v *InferenceSchedulerCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// gatewayWarnings returns the gateway exposure settings that do not take effect. They depend on the
+// gateway implementation, so they are reported but admitted, as by the GatewayConfigValid condition
+func gatewayWarnings(infScheduler *llmv1alpha1.InferenceScheduler) admission.Warnings {
+	if infScheduler.Spec.Gateway.ExistingGatewayRef != nil {
+		return nil
+	}
+	return validation.GatewayExposure(infScheduler.Spec.Gateway)
+}
+
+// validateInferenceScheduler returns an Invalid error listing every invalid field, or nil
+func validateInferenceScheduler(infScheduler *llmv1alpha1.InferenceScheduler) error {
+	epp := infScheduler.Spec.EndpointPicker
+	eppPath := field.NewPath("spec", "endpointPicker")
+	errs := validatePlugins(epp.Plugins, eppPath.Child("plugins"))
+	// The same check as the controller's, so both agree on what counts as configured plugins
+	if err := validation.PluginConfigSource(epp); err != nil {
+		errs = append(errs, field.Forbidden(eppPath.Child("plugins"), "cannot be set with pluginConfigMapRef"))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(llmv1alpha1.GroupVersion.WithKind("InferenceScheduler").GroupKind(), infScheduler.Name, errs)
+}
+
+// validatePlugins checks the weight and parameters of every configured scorer, enabled or not,
+// so a disabled scorer cannot be enabled later with a configuration that was never checked
+func validatePlugins(plugins llmv1alpha1.PluginConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, scorer := range []struct {
+		name   string
+		plugin *llmv1alpha1.ScorerPlugin
+	}{
+		{"loadAwareScorer", plugins.LoadAwareScorer},
+		{"prefixCacheScorer", plugins.PrefixCacheScorer},
+		{"kvCacheUtilizationScorer", plugins.KVCacheUtilizationScorer},
+		{"sessionAffinityScorer", plugins.SessionAffinityScorer},
+		{"loraAffinityScorer", plugins.LoraAffinityScorer},
+	} {
+		if scorer.plugin != nil {
+			errs = append(errs, validateScorer(scorer.name, scorer.plugin, path.Child(scorer.name))...)
+		}
+	}
+
+	// Parameters of additional scorers are unknown to the operator and passed through unchecked
+	for i, scorer := range plugins.AdditionalScorers {
+		scorerPath := path.Child("additionalScorers").Index(i)
+		if scorer.Weight != nil && *scorer.Weight < 0 {
+			errs = append(errs, field.Invalid(scorerPath.Child("weight"), *scorer.Weight, "must not be negative"))
+		}
+		if typed, ok := typedScorerTypes[scorer.Type]; ok {
+			errs = append(errs, field.Invalid(scorerPath.Child("type"), scorer.Type,
+				fmt.Sprintf("must be configured through %s instead", path.Child(typed))))
+		}
+	}
+	return errs
+}
+
+// validateScorer checks that the weight is not negative and that every parameter is known to the
+// scorer and has a value of the right type
+func validateScorer(name string, scorer *llmv1alpha1.ScorerPlugin, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if scorer.Weight != nil && *scorer.Weight < 0 {
+		errs = append(errs, field.Invalid(path.Child("weight"), *scorer.Weight, "must not be negative"))
+	}
+
+	known := scorerParameters[name]
+	keys := make([]string, 0, len(scorer.Parameters))
+	for key := range scorer.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := scorer.Parameters[key]
+		parse, ok := known[key]
+		if !ok {
+			errs = append(errs, field.NotSupported(path.Child("parameters").Key(key), key, knownKeys(known)))
+			continue
+		}
+		if err := parse(value); err != nil {
+			errs = append(errs, field.Invalid(path.Child("parameters").Key(key), value, err.Error()))
+		}
+	}
+	return errs
+}
+
+// knownKeys returns the sorted parameter keys a scorer accepts
+func knownKeys(known map[string]func(string) error) []string {
+	keys := make([]string, 0, len(known))
+	for key := range known {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 Aneesh Puttur.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	llmv1alpha1 "github.com/aneeshkp/inference-scheduler-operator/api/v1alpha1"
+)
+
+var _ = Describe("InferenceScheduler Webhook", func() {
+	var (
+		obj       *llmv1alpha1.InferenceScheduler
+		validator InferenceSchedulerCustomValidator
+	)
+
+	// causes returns the field paths and messages of an Invalid error
+	causes := func(err error) []string {
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		var fields []string
+		for _, cause := range err.(*apierrors.StatusError).Status().Details.Causes {
+			fields = append(fields, cause.Field+": "+cause.Message)
+		}
+		return fields
+	}
+
+	BeforeEach(func() {
+		weight := 1.0
+		obj = &llmv1alpha1.InferenceScheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-scheduler", Namespace: "default"},
+			Spec: llmv1alpha1.InferenceSchedulerSpec{
+				ModelServer: llmv1alpha1.ModelServerSpec{ModelName: "Qwen/Qwen2.5-0.5B-Instruct"},
+				EndpointPicker: llmv1alpha1.EndpointPickerSpec{
+					Plugins: llmv1alpha1.PluginConfig{
+						LoadAwareScorer: &llmv1alpha1.ScorerPlugin{
+							Enabled:    true,
+							Weight:     &weight,
+							Parameters: map[string]string{"queueThreshold": "64"},
+						},
+						PrefixCacheScorer: &llmv1alpha1.ScorerPlugin{
+							Enabled:    true,
+							Parameters: map[string]string{"cacheHitBonus": "1.5"},
+						},
+						KVCacheUtilizationScorer: &llmv1alpha1.ScorerPlugin{Enabled: true},
+					},
+				},
+			},
+		}
+	})
+
+	It("should admit a valid plugin configuration on creation and update", func() {
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = validator.ValidateUpdate(context.Background(), obj.DeepCopy(), obj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a negative weight", func() {
+		weight := -1.0
+		obj.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer.Weight = &weight
+
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(causes(err)).To(ConsistOf(
+			"spec.endpointPicker.plugins.kvCacheUtilizationScorer.weight: Invalid value: -1: must not be negative",
+		))
+	})
+
+	It("should reject a queueThreshold that is not an integer", func() {
+		obj.Spec.EndpointPicker.Plugins.LoadAwareScorer.Parameters["queueThreshold"] = "abc"
+
+		_, err := validator.ValidateUpdate(context.Background(), obj.DeepCopy(), obj)
+		Expect(causes(err)).To(ConsistOf(
+			`spec.endpointPicker.plugins.loadAwareScorer.parameters[queueThreshold]: Invalid value: "abc": must be an integer`,
+		))
+	})
+
+	It("should reject a cacheHitBonus that is not a number", func() {
+		obj.Spec.EndpointPicker.Plugins.PrefixCacheScorer.Parameters["cacheHitBonus"] = "high"
+
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(causes(err)).To(ConsistOf(
+			`spec.endpointPicker.plugins.prefixCacheScorer.parameters[cacheHitBonus]: Invalid value: "high": must be a number`,
+		))
+	})
+
+	It("should reject unknown parameter keys", func() {
+		obj.Spec.EndpointPicker.Plugins.LoadAwareScorer.Parameters["cacheHitBonus"] = "1.0"
+		obj.Spec.EndpointPicker.Plugins.KVCacheUtilizationScorer.Parameters = map[string]string{"threshold": "0.8"}
+
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(causes(err)).To(ConsistOf(
+			`spec.endpointPicker.plugins.loadAwareScorer.parameters[cacheHitBonus]: Unsupported value: "cacheHitBonus": supported values: "queueThreshold"`,
+			`spec.endpointPicker.plugins.kvCacheUtilizationScorer.parameters[threshold]: Unsupported value: "threshold"`,
+		))
+	})
+
+	It("should check disabled scorers too", func() {
+		obj.Spec.EndpointPicker.Plugins.PrefixCacheScorer.Enabled = false
+		obj.Spec.EndpointPicker.Plugins.PrefixCacheScorer.Parameters["cacheHitBonus"] = "high"
+
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should pass additional scorer parameters through but check their weight and type", func() {
+		weight := -0.5
+		obj.Spec.EndpointPicker.Plugins.AdditionalScorers = []llmv1alpha1.AdditionalScorerPlugin{
+			{Type: "queue-scorer", Parameters: map[string]string{"anything": "goes"}},
+			{Type: "session-affinity-scorer", Weight: &weight},
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(causes(err)).To(ConsistOf(
+			"spec.endpointPicker.plugins.additionalScorers[1].weight: Invalid value: -0.5: must not be negative",
+			`spec.endpointPicker.plugins.additionalScorers[1].type: Invalid value: "session-affinity-scorer": `+
+				"must be configured through spec.endpointPicker.plugins.sessionAffinityScorer instead",
+		))
+	})
+
+	It("should reject plugins set together with a config override", func() {
+		obj.Spec.EndpointPicker.PluginConfigMapRef = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "custom-epp-config"},
+			Key:                  "plugins.yaml",
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(causes(err)).To(ConsistOf("spec.endpointPicker.plugins: Forbidden: cannot be set with pluginConfigMapRef"))
+
+		obj.Spec.EndpointPicker.Plugins = llmv1alpha1.PluginConfig{}
+		_, err = validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+
+		// An empty list configures no scorer, as for the controller
+		obj.Spec.EndpointPicker.Plugins.AdditionalScorers = []llmv1alpha1.AdditionalScorerPlugin{}
+		_, err = validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should warn about gateway exposure settings that do not take effect but admit them", func() {
+		warnings, err := validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+
+		obj.Spec.Gateway.ClassName = "gke-l7-regional-external-managed"
+		obj.Spec.Gateway.ListenerPort = 8080
+		warnings, err = validator.ValidateCreate(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("only supports listener ports 80 and 443, not 8080")))
+
+		obj.Spec.Gateway.ClassName = "istio"
+		obj.Spec.Gateway.ListenerPort = 80
+		obj.Spec.Gateway.ServiceType = "LoadBalancer"
+		warnings, err = validator.ValidateUpdate(context.Background(), obj.DeepCopy(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("serviceType LoadBalancer is not applied")))
+
+		// No Gateway is created for an existing Gateway, so its exposure is not the operator's
+		obj.Spec.Gateway.ExistingGatewayRef = &llmv1alpha1.GatewayReference{Name: "shared"}
+		warnings, err = validator.ValidateUpdate(context.Background(), obj.DeepCopy(), obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should allow deletion", func() {
+		obj.Spec.EndpointPicker.Plugins.LoadAwareScorer.Parameters["queueThreshold"] = "abc"
+		_, err := validator.ValidateDelete(context.Background(), obj)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})